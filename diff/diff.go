@@ -0,0 +1,244 @@
+// Package diff compares node trees and reports changes by JSON Pointer.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/a-skua/json-parser/internal/align"
+	"github.com/a-skua/json-parser/node"
+	"github.com/a-skua/json-parser/pointer"
+)
+
+type Kind uint8
+
+const (
+	_ Kind = iota
+	Added
+	Removed
+	Changed
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is a difference at Path. From is nil for Added, To for Removed.
+// Paths of removed values refer to the old tree, all others to the new one.
+type Change struct {
+	Kind Kind
+	Path pointer.Pointer
+	From node.Node
+	To   node.Node
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s %s: %s", c.Kind, c.Path, c.To)
+	case Removed:
+		return fmt.Sprintf("%s %s: %s", c.Kind, c.Path, c.From)
+	default:
+		return fmt.Sprintf("%s %s: %s -> %s", c.Kind, c.Path, c.From, c.To)
+	}
+}
+
+type Option func(*options)
+
+type options struct {
+	align bool
+}
+
+// AlignArrays matches array elements by their longest common subsequence
+// instead of by index, so that an insertion is reported as a single addition.
+func AlignArrays() Option {
+	return func(o *options) {
+		o.align = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Compare returns the changes from a to b in document order.
+func Compare(a, b node.Node, opts ...Option) []Change {
+	d := differ{opts: newOptions(opts)}
+	d.compare(pointer.Pointer{}, a, b)
+	return d.changes
+}
+
+type differ struct {
+	opts    options
+	changes []Change
+}
+
+func (d *differ) add(kind Kind, p pointer.Pointer, from, to node.Node) {
+	d.changes = append(d.changes, Change{kind, p, from, to})
+}
+
+func (d *differ) compare(p pointer.Pointer, a, b node.Node) {
+	switch a := a.(type) {
+	case node.Object:
+		if b, ok := b.(node.Object); ok {
+			d.compareObjects(p, a, b)
+			return
+		}
+	case node.Array:
+		if b, ok := b.(node.Array); ok {
+			if d.opts.align {
+				d.alignArrays(p, a, b)
+			} else {
+				d.compareArrays(p, a, b)
+			}
+			return
+		}
+	}
+	if !node.Equal(a, b) {
+		d.add(Changed, p, a, b)
+	}
+}
+
+func (d *differ) compareObjects(p pointer.Pointer, a, b node.Object) {
+	keys := uniq(a.Keys())
+	for _, key := range keys {
+		x, _ := a.Get(key)
+		if y, ok := b.Get(key); ok {
+			d.compare(p.Append(key), x, y)
+		} else {
+			d.add(Removed, p.Append(key), x, nil)
+		}
+	}
+	for _, key := range uniq(b.Keys()) {
+		if !a.Has(key) {
+			y, _ := b.Get(key)
+			d.add(Added, p.Append(key), nil, y)
+		}
+	}
+}
+
+func (d *differ) compareArrays(p pointer.Pointer, a, b node.Array) {
+	x := a.Nodes()
+	y := b.Nodes()
+	for i := 0; i < len(x) || i < len(y); i++ {
+		path := p.Append(strconv.Itoa(i))
+		switch {
+		case len(y) <= i:
+			d.add(Removed, path, x[i], nil)
+		case len(x) <= i:
+			d.add(Added, path, nil, y[i])
+		default:
+			d.compare(path, x[i], y[i])
+		}
+	}
+}
+
+// alignArrays keeps the longest common subsequence of equal elements.
+// Between two kept elements, the remaining elements are compared pairwise
+// and the surplus is reported as removed or added. Arrays whose differing
+// elements are too many to align are compared index by index.
+func (d *differ) alignArrays(p pointer.Pointer, a, b node.Array) {
+	arrays := align.Trim(a.Nodes(), b.Nodes())
+	x, y := arrays.X, arrays.Y
+	index := func(i int) pointer.Pointer {
+		return p.Append(strconv.Itoa(arrays.Offset + i))
+	}
+
+	i, j := 0, 0
+	gap := func(endX, endY int) {
+		for ; i < endX && j < endY; i, j = i+1, j+1 {
+			d.compare(index(j), x[i], y[j])
+		}
+		for ; i < endX; i++ {
+			d.add(Removed, index(i), x[i], nil)
+		}
+		for ; j < endY; j++ {
+			d.add(Added, index(j), nil, y[j])
+		}
+	}
+	if !arrays.Fits() {
+		gap(len(x), len(y))
+		return
+	}
+
+	// lcs[i][j] is the length of the LCS of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if arrays.Equal(i, j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	startX, startY := 0, 0
+	for startX < len(x) && startY < len(y) {
+		switch {
+		case arrays.Equal(startX, startY):
+			gap(startX, startY)
+			i, j = startX+1, startY+1
+			startX, startY = i, j
+		case lcs[startX+1][startY] >= lcs[startX][startY+1]:
+			startX++
+		default:
+			startY++
+		}
+	}
+	gap(len(x), len(y))
+}
+
+func uniq(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// Render writes changes in a unified-diff-like format: a "---"/"+++" header
+// naming the trees, then a hunk per change with its path, the removed value
+// prefixed by '-' and the added value by '+'.
+func Render(w io.Writer, from, to string, changes []Change) error {
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", from, to); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if _, err := fmt.Fprintf(w, "@@ %s @@\n", c.Path); err != nil {
+			return err
+		}
+		if c.From != nil {
+			if _, err := fmt.Fprintf(w, "-%s\n", c.From); err != nil {
+				return err
+			}
+		}
+		if c.To != nil {
+			if _, err := fmt.Fprintf(w, "+%s\n", c.To); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
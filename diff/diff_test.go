@@ -0,0 +1,193 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-skua/json-parser/node"
+)
+
+func TestCompare(t *testing.T) {
+	tests := map[string]struct {
+		a    string
+		b    string
+		opts []Option
+		want []string
+	}{
+		"equal": {
+			a:    `{"a": [1, {"b": null}], "c": "d"}`,
+			b:    `{"c": "d", "a": [1.0, {"b": null}]}`,
+			want: []string{},
+		},
+		"root": {
+			a:    `1`,
+			b:    `2`,
+			want: []string{"changed : 1 -> 2"},
+		},
+		"type": {
+			a:    `{"a": [1]}`,
+			b:    `{"a": {"0": 1}}`,
+			want: []string{`changed /a: [1] -> {"0":1}`},
+		},
+		"members": {
+			a: `{"a": 1, "b": {"c": true}, "d": "e"}`,
+			b: `{"f": 2, "b": {"c": false}, "a": 1}`,
+			want: []string{
+				"changed /b/c: true -> false",
+				`removed /d: "e"`,
+				"added /f: 2",
+			},
+		},
+		"escaped keys": {
+			a:    `{"a/b": {"~": 1}}`,
+			b:    `{"a/b": {"~": 2}}`,
+			want: []string{"changed /a~1b/~0: 1 -> 2"},
+		},
+		"array by index": {
+			a: `[1, 2, 3]`,
+			b: `[0, 1, 2, 3]`,
+			want: []string{
+				"changed /0: 1 -> 0",
+				"changed /1: 2 -> 1",
+				"changed /2: 3 -> 2",
+				"added /3: 3",
+			},
+		},
+		"array by index: removed": {
+			a: `[1, 2, 3]`,
+			b: `[1]`,
+			want: []string{
+				"removed /1: 2",
+				"removed /2: 3",
+			},
+		},
+		"aligned: inserted": {
+			a:    `[1, 2, 3]`,
+			b:    `[0, 1, 2, 3]`,
+			opts: []Option{AlignArrays()},
+			want: []string{"added /0: 0"},
+		},
+		"aligned: removed": {
+			a:    `["a", "b", "c", "d"]`,
+			b:    `["a", "d"]`,
+			opts: []Option{AlignArrays()},
+			want: []string{
+				`removed /1: "b"`,
+				`removed /2: "c"`,
+			},
+		},
+		"aligned: changed between common elements": {
+			a:    `["a", {"x": 1}, "c"]`,
+			b:    `["a", {"x": 2}, "c", "d"]`,
+			opts: []Option{AlignArrays()},
+			want: []string{
+				"changed /1/x: 1 -> 2",
+				`added /3: "d"`,
+			},
+		},
+		"aligned: moved": {
+			a:    `[1, 2, 3]`,
+			b:    `[3, 1, 2]`,
+			opts: []Option{AlignArrays()},
+			want: []string{
+				"added /0: 3",
+				"removed /2: 3",
+			},
+		},
+		"aligned: nested": {
+			a:    `{"a": [[1, 2], [3]]}`,
+			b:    `{"a": [[0], [1, 2], [3]]}`,
+			opts: []Option{AlignArrays()},
+			want: []string{"added /a/0: [0]"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, err := node.Parse(tt.a)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.a, err)
+			}
+			b, err := node.Parse(tt.b)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.b, err)
+			}
+
+			changes := Compare(a, b, tt.opts...)
+			got := make([]string, len(changes))
+			for i, c := range changes {
+				got[i] = c.String()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("Compare(%s, %s) = %q (want: %q)", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare_largeArrays(t *testing.T) {
+	numbers := func(n, from int) []node.Node {
+		nodes := make([]node.Node, n)
+		for i := range nodes {
+			nodes[i] = node.NewNumberFromInt(int64(from + i))
+		}
+		return nodes
+	}
+	changed := numbers(20000, 0)
+	changed[12345] = node.NewString("changed")
+
+	tests := map[string]struct {
+		a, b    node.Array
+		wantLen int
+		want    string
+	}{
+		"one element changed": {
+			a:       node.NewArray(numbers(20000, 0)...),
+			b:       node.NewArray(changed...),
+			wantLen: 1,
+			want:    `changed /12345: 12345 -> "changed"`,
+		},
+		"all elements changed": {
+			a:       node.NewArray(numbers(1100, 0)...),
+			b:       node.NewArray(numbers(1000, 5000)...),
+			wantLen: 1100,
+			want:    "changed /0: 0 -> 5000",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			changes := Compare(tt.a, tt.b, AlignArrays())
+			if len(changes) != tt.wantLen {
+				t.Fatalf("Compare() returned %d changes (want: %d)", len(changes), tt.wantLen)
+			}
+			if got := changes[0].String(); got != tt.want {
+				t.Fatalf("Compare()[0] = %s (want: %s)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	a, _ := node.Parse(`{"name": "app", "replicas": 2, "ports": [80]}`)
+	b, _ := node.Parse(`{"name": "app", "replicas": 3, "ports": [80, 443], "debug": true}`)
+
+	var w strings.Builder
+	if err := Render(&w, "a.json", "b.json", Compare(a, b)); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	want := `--- a.json
++++ b.json
+@@ /replicas @@
+-2
++3
+@@ /ports/1 @@
++443
+@@ /debug @@
++true
+`
+	if got := w.String(); got != want {
+		t.Fatalf("Render() = %s (want: %s)", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+// Package align prepares two arrays for the alignment of their elements.
+package align
+
+import (
+	"github.com/a-skua/json-parser/node"
+)
+
+// MaxCells bounds the (len(X)+1)*(len(Y)+1) cells of an alignment table.
+const MaxCells = 1 << 20
+
+// Arrays holds two arrays without their common prefix and suffix.
+type Arrays struct {
+	X, Y []node.Node
+	// Offset is the length of the common prefix.
+	Offset int
+
+	hx, hy []uint64
+}
+
+// Trim strips the common prefix and suffix of x and y.
+func Trim(x, y []node.Node) *Arrays {
+	offset := 0
+	for offset < len(x) && offset < len(y) && node.Equal(x[offset], y[offset]) {
+		offset++
+	}
+	x, y = x[offset:], y[offset:]
+	for len(x) > 0 && len(y) > 0 && node.Equal(x[len(x)-1], y[len(y)-1]) {
+		x, y = x[:len(x)-1], y[:len(y)-1]
+	}
+	return &Arrays{X: x, Y: y, Offset: offset}
+}
+
+// Fits reports whether a table aligning X and Y has at most MaxCells cells.
+// Callers compare larger arrays index by index instead.
+func (a *Arrays) Fits() bool {
+	return (len(a.X)+1)*(len(a.Y)+1) <= MaxCells
+}
+
+// Equal reports whether X[i] and Y[j] are equal, comparing their hashes
+// before node.Equal.
+func (a *Arrays) Equal(i, j int) bool {
+	if a.hx == nil {
+		a.hx = hashes(a.X)
+		a.hy = hashes(a.Y)
+	}
+	return a.hx[i] == a.hy[j] && node.Equal(a.X[i], a.Y[j])
+}
+
+func hashes(nodes []node.Node) []uint64 {
+	h := make([]uint64, len(nodes))
+	for i, n := range nodes {
+		h[i] = node.Hash(n)
+	}
+	return h
+}
@@ -0,0 +1,64 @@
+package align
+
+import (
+	"testing"
+
+	"github.com/a-skua/json-parser/node"
+)
+
+func TestTrim(t *testing.T) {
+	tests := map[string]struct {
+		x, y       string
+		wantX      string
+		wantY      string
+		wantOffset int
+	}{
+		"equal": {
+			x: `[1, 2]`, y: `[1.0, 2]`,
+			wantX: `[]`, wantY: `[]`, wantOffset: 2,
+		},
+		"middle": {
+			x: `[1, 2, 3, 4]`, y: `[1, "a", "b", 4]`,
+			wantX: `[2,3]`, wantY: `["a","b"]`, wantOffset: 1,
+		},
+		"insertion": {
+			x: `[1, 1]`, y: `[1, 1, 1]`,
+			wantX: `[]`, wantY: `[1]`, wantOffset: 2,
+		},
+		"disjoint": {
+			x: `[1]`, y: `[{}]`,
+			wantX: `[1]`, wantY: `[{}]`, wantOffset: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			x, err := node.Parse(tt.x)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.x, err)
+			}
+			y, err := node.Parse(tt.y)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.y, err)
+			}
+
+			a := Trim(x.(node.Array).Nodes(), y.(node.Array).Nodes())
+			if got := node.NewArray(a.X...).String(); got != tt.wantX {
+				t.Errorf("Trim().X = %s (want: %s)", got, tt.wantX)
+			}
+			if got := node.NewArray(a.Y...).String(); got != tt.wantY {
+				t.Errorf("Trim().Y = %s (want: %s)", got, tt.wantY)
+			}
+			if a.Offset != tt.wantOffset {
+				t.Errorf("Trim().Offset = %d (want: %d)", a.Offset, tt.wantOffset)
+			}
+			for i := range a.X {
+				for j := range a.Y {
+					if a.Equal(i, j) != node.Equal(a.X[i], a.Y[j]) {
+						t.Errorf("Equal(%d, %d) = %v", i, j, a.Equal(i, j))
+					}
+				}
+			}
+		})
+	}
+}
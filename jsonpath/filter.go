@@ -0,0 +1,309 @@
+package jsonpath
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/a-skua/json-parser/node"
+)
+
+// logicalExpr is a filter expression of LogicalType.
+type logicalExpr interface {
+	test(current, root node.Node) bool
+}
+
+// valueExpr is an expression of ValueType. A nil node is Nothing.
+type valueExpr interface {
+	value(current, root node.Node) node.Node
+}
+
+type orExpr []logicalExpr
+
+func (e orExpr) test(current, root node.Node) bool {
+	for _, expr := range e {
+		if expr.test(current, root) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []logicalExpr
+
+func (e andExpr) test(current, root node.Node) bool {
+	for _, expr := range e {
+		if !expr.test(current, root) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct {
+	expr logicalExpr
+}
+
+func (e notExpr) test(current, root node.Node) bool {
+	return !e.expr.test(current, root)
+}
+
+// existenceExpr tests whether a query selects any node.
+type existenceExpr struct {
+	query query
+}
+
+func (e existenceExpr) test(current, root node.Node) bool {
+	return len(e.query.evaluate(current, root)) > 0
+}
+
+type comparisonExpr struct {
+	left  valueExpr
+	op    string
+	right valueExpr
+}
+
+func (e comparisonExpr) test(current, root node.Node) bool {
+	left, right := e.left.value(current, root), e.right.value(current, root)
+	switch e.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "<":
+		return less(left, right)
+	case "<=":
+		return less(left, right) || equal(left, right)
+	case ">":
+		return less(right, left)
+	default: // ">="
+		return less(right, left) || equal(left, right)
+	}
+}
+
+type literalExpr struct {
+	node node.Node
+}
+
+func (e literalExpr) value(_, _ node.Node) node.Node {
+	return e.node
+}
+
+// singularQueryExpr is the value of the node selected by a singular query.
+type singularQueryExpr struct {
+	query query
+}
+
+func (e singularQueryExpr) value(current, root node.Node) node.Node {
+	nodes := e.query.evaluate(current, root)
+	if len(nodes) != 1 {
+		return nil
+	}
+	return nodes[0]
+}
+
+func equal(a, b node.Node) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return node.Equal(a, b)
+}
+
+func less(a, b node.Node) bool {
+	switch a := a.(type) {
+	case node.Number:
+		b, ok := b.(node.Number)
+		return ok && compareNumbers(a, b) < 0
+	case node.String:
+		b, ok := b.(node.String)
+		return ok && a.Value().(string) < b.Value().(string)
+	default:
+		return false
+	}
+}
+
+func compareNumbers(a, b node.Number) int {
+	x, errX := a.Float64()
+	y, errY := b.Float64()
+	if errX == nil && errY == nil && x != y {
+		if x < y {
+			return -1
+		}
+		return 1
+	}
+
+	bx, errX := a.BigFloat()
+	by, errY := b.BigFloat()
+	if errX != nil || errY != nil {
+		return strings.Compare(a.String(), b.String())
+	}
+	return bx.Cmp(by)
+}
+
+// paramType is the declared type of a function parameter or result.
+type paramType uint8
+
+const (
+	_ paramType = iota
+	valueType
+	logicalType
+	nodesType
+)
+
+// patternMatch is how match and search apply their pattern argument.
+type patternMatch uint8
+
+const (
+	_ patternMatch = iota
+	matchFull
+	matchPartial
+)
+
+type function struct {
+	params []paramType
+	result paramType
+	// pattern is set for functions whose second argument is an I-Regexp.
+	pattern patternMatch
+	// call receives node.Node for valueType and []node.Node for nodesType arguments,
+	// and returns node.Node for valueType and bool for logicalType results.
+	call func(args []interface{}) interface{}
+}
+
+var functions = map[string]function{
+	"length": {
+		params: []paramType{valueType},
+		result: valueType,
+		call: func(args []interface{}) interface{} {
+			switch v := args[0].(type) {
+			case node.String:
+				return node.NewNumberFromInt(int64(utf8.RuneCountInString(v.Value().(string))))
+			case node.Array:
+				return node.NewNumberFromInt(int64(v.Len()))
+			case node.Object:
+				return node.NewNumberFromInt(int64(v.Len()))
+			default:
+				return nil
+			}
+		},
+	},
+	"count": {
+		params: []paramType{nodesType},
+		result: valueType,
+		call: func(args []interface{}) interface{} {
+			return node.NewNumberFromInt(int64(len(args[0].([]node.Node))))
+		},
+	},
+	"match": {
+		params:  []paramType{valueType, valueType},
+		pattern: matchFull,
+		result:  logicalType,
+		call: func(args []interface{}) interface{} {
+			return matchRegexp(args[0], args[1], true)
+		},
+	},
+	"search": {
+		params:  []paramType{valueType, valueType},
+		pattern: matchPartial,
+		result:  logicalType,
+		call: func(args []interface{}) interface{} {
+			return matchRegexp(args[0], args[1], false)
+		},
+	},
+	"value": {
+		params: []paramType{nodesType},
+		result: valueType,
+		call: func(args []interface{}) interface{} {
+			nodes := args[0].([]node.Node)
+			if len(nodes) != 1 {
+				return nil
+			}
+			return nodes[0]
+		},
+	},
+}
+
+type functionExpr struct {
+	name string
+	fn   function
+	args []interface{} // valueExpr or query
+
+	// pattern is the compiled literal pattern of match and search, if any.
+	pattern *regexp.Regexp
+}
+
+func (e functionExpr) call(current, root node.Node) interface{} {
+	args := make([]interface{}, len(e.args))
+	for i, arg := range e.args {
+		switch arg := arg.(type) {
+		case valueExpr:
+			args[i] = arg.value(current, root)
+		case query:
+			args[i] = arg.evaluate(current, root)
+		}
+	}
+	if e.pattern != nil {
+		s, ok := args[0].(node.String)
+		return ok && e.pattern.MatchString(s.Value().(string))
+	}
+	return e.fn.call(args)
+}
+
+func (e functionExpr) value(current, root node.Node) node.Node {
+	v, _ := e.call(current, root).(node.Node)
+	return v
+}
+
+func (e functionExpr) test(current, root node.Node) bool {
+	return e.call(current, root).(bool)
+}
+
+func matchRegexp(s, pattern interface{}, full bool) bool {
+	str, ok := s.(node.String)
+	if !ok {
+		return false
+	}
+	p, ok := pattern.(node.String)
+	if !ok {
+		return false
+	}
+	re, err := compileRegexp(p.Value().(string), full)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(str.Value().(string))
+}
+
+// compileRegexp compiles an I-Regexp matching the whole string if full.
+func compileRegexp(pattern string, full bool) (*regexp.Regexp, error) {
+	expr := translateRegexp(pattern)
+	if full {
+		expr = `^(?:` + expr + `)$`
+	}
+	return regexp.Compile(expr)
+}
+
+// translateRegexp converts an I-Regexp (RFC 9485) to RE2 syntax:
+// '.' outside character classes does not match '\n' or '\r'.
+func translateRegexp(pattern string) string {
+	var b strings.Builder
+	class := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			b.WriteByte(c)
+			i++
+			b.WriteByte(pattern[i])
+			continue
+		case c == '[':
+			class = true
+		case c == ']':
+			class = false
+		case c == '.' && !class:
+			b.WriteString(`[^\n\r]`)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
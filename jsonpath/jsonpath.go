@@ -0,0 +1,246 @@
+// Package jsonpath implements JSONPath (RFC 9535) queries over node trees.
+package jsonpath
+
+import (
+	"fmt"
+
+	"github.com/a-skua/json-parser/node"
+)
+
+// SyntaxError reports an invalid expression and the byte offset of the error.
+type SyntaxError struct {
+	Expr   string
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d: %q", e.Msg, e.Offset, e.Expr)
+}
+
+// Path is a compiled JSONPath expression, safe for concurrent use.
+type Path struct {
+	expr  string
+	query query
+}
+
+func Compile(expr string) (*Path, error) {
+	p := parser{expr: expr}
+	q, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Path{expr: expr, query: q}, nil
+}
+
+// MustCompile is like Compile but panics on an invalid expression.
+func MustCompile(expr string) *Path {
+	p, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Query compiles expr and evaluates it against root.
+func Query(expr string, root node.Node) ([]node.Node, error) {
+	p, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Evaluate(root), nil
+}
+
+// Evaluate returns the nodes selected from root in document order.
+func (p *Path) Evaluate(root node.Node) []node.Node {
+	return p.query.evaluate(root, root)
+}
+
+func (p *Path) String() string {
+	return p.expr
+}
+
+type query struct {
+	relative bool
+	segments []segment
+}
+
+// evaluate applies the query to current (relative) or root (absolute).
+func (q query) evaluate(current, root node.Node) []node.Node {
+	nodes := []node.Node{root}
+	if q.relative {
+		nodes = []node.Node{current}
+	}
+	for _, s := range q.segments {
+		nodes = s.evaluate(nodes, root)
+	}
+	return nodes
+}
+
+// singular reports whether the query selects at most one node.
+func (q query) singular() bool {
+	for _, s := range q.segments {
+		if s.descendant || len(s.selectors) != 1 {
+			return false
+		}
+		switch s.selectors[0].(type) {
+		case nameSelector, indexSelector:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+func (s segment) evaluate(input []node.Node, root node.Node) []node.Node {
+	output := []node.Node{}
+	for _, n := range input {
+		if !s.descendant {
+			output = s.apply(output, n, root)
+			continue
+		}
+		for _, d := range descendants(n, nil) {
+			output = s.apply(output, d, root)
+		}
+	}
+	return output
+}
+
+func (s segment) apply(output []node.Node, n, root node.Node) []node.Node {
+	for _, sel := range s.selectors {
+		output = sel.apply(output, n, root)
+	}
+	return output
+}
+
+// descendants returns n and its descendants in document order.
+func descendants(n node.Node, output []node.Node) []node.Node {
+	output = append(output, n)
+	for _, child := range children(n) {
+		output = descendants(child, output)
+	}
+	return output
+}
+
+func children(n node.Node) []node.Node {
+	switch n := n.(type) {
+	case node.Array:
+		return n.Nodes()
+	case node.Object:
+		fields := n.Fields()
+		nodes := make([]node.Node, len(fields))
+		for i, f := range fields {
+			nodes[i] = f.Value
+		}
+		return nodes
+	default:
+		return nil
+	}
+}
+
+type selector interface {
+	apply(output []node.Node, n, root node.Node) []node.Node
+}
+
+type nameSelector string
+
+func (s nameSelector) apply(output []node.Node, n, _ node.Node) []node.Node {
+	if o, ok := n.(node.Object); ok {
+		if v, ok := o.Get(string(s)); ok {
+			output = append(output, v)
+		}
+	}
+	return output
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) apply(output []node.Node, n, _ node.Node) []node.Node {
+	return append(output, children(n)...)
+}
+
+type indexSelector int
+
+func (s indexSelector) apply(output []node.Node, n, _ node.Node) []node.Node {
+	a, ok := n.(node.Array)
+	if !ok {
+		return output
+	}
+	i := int(s)
+	if i < 0 {
+		i += a.Len()
+	}
+	if v, ok := a.At(i); ok {
+		output = append(output, v)
+	}
+	return output
+}
+
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) apply(output []node.Node, n, _ node.Node) []node.Node {
+	a, ok := n.(node.Array)
+	if !ok || s.step == 0 {
+		return output
+	}
+
+	nodes := a.Nodes()
+	length := len(nodes)
+	normalize := func(i int) int {
+		if i < 0 {
+			return length + i
+		}
+		return i
+	}
+
+	if s.step > 0 {
+		start, end := 0, length
+		if s.start != nil {
+			start = normalize(*s.start)
+		}
+		if s.end != nil {
+			end = normalize(*s.end)
+		}
+		lower := min(max(start, 0), length)
+		upper := min(max(end, 0), length)
+		for i := lower; i < upper; i += s.step {
+			output = append(output, nodes[i])
+		}
+		return output
+	}
+
+	start, end := length-1, -length-1
+	if s.start != nil {
+		start = normalize(*s.start)
+	}
+	if s.end != nil {
+		end = normalize(*s.end)
+	}
+	upper := min(max(start, -1), length-1)
+	lower := min(max(end, -1), length-1)
+	for i := upper; lower < i; i += s.step {
+		output = append(output, nodes[i])
+	}
+	return output
+}
+
+type filterSelector struct {
+	expr logicalExpr
+}
+
+func (s filterSelector) apply(output []node.Node, n, root node.Node) []node.Node {
+	for _, child := range children(n) {
+		if s.expr.test(child, root) {
+			output = append(output, child)
+		}
+	}
+	return output
+}
@@ -0,0 +1,442 @@
+package jsonpath
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/a-skua/json-parser/node"
+)
+
+// store is the example of RFC 9535 section 1.5.
+const store = `{ "store": {
+    "book": [
+      { "category": "reference",
+        "author": "Nigel Rees",
+        "title": "Sayings of the Century",
+        "price": 8.95
+      },
+      { "category": "fiction",
+        "author": "Evelyn Waugh",
+        "title": "Sword of Honour",
+        "price": 12.99
+      },
+      { "category": "fiction",
+        "author": "Herman Melville",
+        "title": "Moby Dick",
+        "isbn": "0-553-21311-3",
+        "price": 8.99
+      },
+      { "category": "fiction",
+        "author": "J. R. R. Tolkien",
+        "title": "The Lord of the Rings",
+        "isbn": "0-395-19395-8",
+        "price": 22.99
+      }
+    ],
+    "bicycle": {
+      "color": "red",
+      "price": 399
+    }
+  }
+}`
+
+func TestPath_Evaluate(t *testing.T) {
+	tests := map[string]struct {
+		document string
+		expr     string
+		want     string
+	}{
+		"root": {
+			document: `{"a": 1}`,
+			expr:     "$",
+			want:     `[{"a":1}]`,
+		},
+		"authors": {
+			document: store,
+			expr:     "$.store.book[*].author",
+			want:     `["Nigel Rees" "Evelyn Waugh" "Herman Melville" "J. R. R. Tolkien"]`,
+		},
+		"all authors": {
+			document: store,
+			expr:     "$..author",
+			want:     `["Nigel Rees" "Evelyn Waugh" "Herman Melville" "J. R. R. Tolkien"]`,
+		},
+		"all things in store": {
+			document: store,
+			expr:     "$.store.*",
+			want:     `[[{"category":"reference","author":"Nigel Rees","title":"Sayings of the Century","price":8.95},{"category":"fiction","author":"Evelyn Waugh","title":"Sword of Honour","price":12.99},{"category":"fiction","author":"Herman Melville","title":"Moby Dick","isbn":"0-553-21311-3","price":8.99},{"category":"fiction","author":"J. R. R. Tolkien","title":"The Lord of the Rings","isbn":"0-395-19395-8","price":22.99}] {"color":"red","price":399}]`,
+		},
+		"prices of everything": {
+			document: store,
+			expr:     "$.store..price",
+			want:     "[8.95 12.99 8.99 22.99 399]",
+		},
+		"third book": {
+			document: store,
+			expr:     "$..book[2].title",
+			want:     `["Moby Dick"]`,
+		},
+		"third book's author": {
+			document: store,
+			expr:     "$..book[2].author",
+			want:     `["Herman Melville"]`,
+		},
+		"third book's publisher": {
+			document: store,
+			expr:     "$..book[2].publisher",
+			want:     "[]",
+		},
+		"last book": {
+			document: store,
+			expr:     "$..book[-1].title",
+			want:     `["The Lord of the Rings"]`,
+		},
+		"first two books": {
+			document: store,
+			expr:     "$..book[0,1].title",
+			want:     `["Sayings of the Century" "Sword of Honour"]`,
+		},
+		"first two books (slice)": {
+			document: store,
+			expr:     "$..book[:2].title",
+			want:     `["Sayings of the Century" "Sword of Honour"]`,
+		},
+		"books with isbn": {
+			document: store,
+			expr:     "$..book[?@.isbn].title",
+			want:     `["Moby Dick" "The Lord of the Rings"]`,
+		},
+		"books cheaper than 10": {
+			document: store,
+			expr:     "$..book[?@.price<10].title",
+			want:     `["Sayings of the Century" "Moby Dick"]`,
+		},
+		"all member values and array elements": {
+			document: `{"a": [1, {"b": 2}]}`,
+			expr:     "$..*",
+			want:     `[[1,{"b":2}] 1 {"b":2} 2]`,
+		},
+		"bracketed names": {
+			document: `{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`,
+			expr:     `$.o['j j']["k.k"]`,
+			want:     "[3]",
+		},
+		"escaped names": {
+			document: `{"'": {"@": 2}, "☺": 1}`,
+			expr:     `$["'"]['@', '☺']`,
+			want:     "[2]",
+		},
+		"unicode shorthand": {
+			document: `{"☺": 1}`,
+			expr:     `$.☺`,
+			want:     "[1]",
+		},
+		"blank space": {
+			document: `{"a": {"b": [1, 2]}}`,
+			expr:     "$ .a [ 'b' ] [ 0 , 1 ]",
+			want:     "[1 2]",
+		},
+		"wildcard on scalar": {
+			document: `1`,
+			expr:     "$.*",
+			want:     "[]",
+		},
+		"index": {
+			document: `["a", "b"]`,
+			expr:     "$[1]",
+			want:     `["b"]`,
+		},
+		"index: negative": {
+			document: `["a", "b"]`,
+			expr:     "$[-2]",
+			want:     `["a"]`,
+		},
+		"index: out of range": {
+			document: `["a", "b"]`,
+			expr:     "$[2]",
+			want:     "[]",
+		},
+		"slice": {
+			document: `["a", "b", "c", "d", "e", "f", "g"]`,
+			expr:     "$[1:3]",
+			want:     `["b" "c"]`,
+		},
+		"slice: from": {
+			document: `["a", "b", "c", "d", "e", "f", "g"]`,
+			expr:     "$[5:]",
+			want:     `["f" "g"]`,
+		},
+		"slice: step": {
+			document: `["a", "b", "c", "d", "e", "f", "g"]`,
+			expr:     "$[1:5:2]",
+			want:     `["b" "d"]`,
+		},
+		"slice: negative step": {
+			document: `["a", "b", "c", "d", "e", "f", "g"]`,
+			expr:     "$[5:1:-2]",
+			want:     `["f" "d"]`,
+		},
+		"slice: reverse": {
+			document: `["a", "b", "c", "d", "e", "f", "g"]`,
+			expr:     "$[::-1]",
+			want:     `["g" "f" "e" "d" "c" "b" "a"]`,
+		},
+		"slice: zero step": {
+			document: `["a", "b"]`,
+			expr:     "$[::0]",
+			want:     "[]",
+		},
+		"slice: out of range": {
+			document: `["a", "b"]`,
+			expr:     "$[-10:10]",
+			want:     `["a" "b"]`,
+		},
+		"descendant index": {
+			document: `{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`,
+			expr:     "$..[0]",
+			want:     `[5 {"j":4}]`,
+		},
+		"descendant name": {
+			document: `{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`,
+			expr:     "$..j",
+			want:     "[1 4]",
+		},
+		"filter: comparison with literal": {
+			document: `{"a": [3, 5, 1, 2, 4, 6, {"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}]}`,
+			expr:     "$.a[?@.b == 'kilo']",
+			want:     `[{"b":"kilo"}]`,
+		},
+		"filter: current node": {
+			document: `{"a": [3, 5, 1, 2, 4, 6, {"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}]}`,
+			expr:     "$.a[?@>3.5]",
+			want:     "[5 4 6]",
+		},
+		"filter: existence": {
+			document: `{"a": [3, 5, 1, 2, 4, 6, {"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}]}`,
+			expr:     "$.a[?@.b]",
+			want:     `[{"b":"j"} {"b":"k"} {"b":{}} {"b":"kilo"}]`,
+		},
+		"filter: object comparison": {
+			document: `{"a": [{"b": {}}, {"b": {"c": 1}}], "o": {}}`,
+			expr:     "$.a[?@.b == $.o]",
+			want:     `[{"b":{}}]`,
+		},
+		"filter: array comparison": {
+			document: `{"a": [[1, 2], [2, 1], 3]}`,
+			expr:     "$.a[?@ == $.a[0]]",
+			want:     "[[1,2]]",
+		},
+		"filter: or": {
+			document: `{"a": [3, 5, 1, 2, 4, 6]}`,
+			expr:     "$.a[?@<2 || @>5]",
+			want:     "[1 6]",
+		},
+		"filter: and with parentheses": {
+			document: `{"a": [3, 5, 1, 2, 4, 6]}`,
+			expr:     "$.a[?(@>1 && @<4)]",
+			want:     "[3 2]",
+		},
+		"filter: not": {
+			document: `{"a": [3, 5, 1, 2, 4, 6]}`,
+			expr:     "$.a[?!(@ != 4)]",
+			want:     "[4]",
+		},
+		"filter: not existence": {
+			document: `[{"a": 1}, {"b": 2}]`,
+			expr:     "$[?!@.a]",
+			want:     `[{"b":2}]`,
+		},
+		"filter: nested filter": {
+			document: `[[1, 2], [3], [4, 5, 6]]`,
+			expr:     "$[?@[?@>4]]",
+			want:     "[[4,5,6]]",
+		},
+		"filter: nothing equals nothing": {
+			document: `[{"a": 1}, {"b": 2}]`,
+			expr:     "$[?@.x == @.y]",
+			want:     `[{"a":1} {"b":2}]`,
+		},
+		"filter: nothing is not null": {
+			document: `[{"a": null}, {"b": 2}]`,
+			expr:     "$[?@.a == null]",
+			want:     `[{"a":null}]`,
+		},
+		"filter: mixed types are not ordered": {
+			document: `[1, "1", true, null]`,
+			expr:     "$[?@ <= 1]",
+			want:     "[1]",
+		},
+		"filter: string ordering": {
+			document: `["a", "b", "c"]`,
+			expr:     `$[?@ > "a"]`,
+			want:     `["b" "c"]`,
+		},
+		"filter: exact numbers": {
+			document: `[9007199254740992, 9007199254740993]`,
+			expr:     "$[?@ == 9007199254740993]",
+			want:     "[9007199254740993]",
+		},
+		"filter: numbers of different spelling": {
+			document: `[1, 1.0, 1e0, 10e-1, 2]`,
+			expr:     "$[?@ == 1]",
+			want:     "[1 1.0 1e0 10e-1]",
+		},
+		"function: length": {
+			document: `["ab", "☺☺☺", [1, 2, 3], {"a": 1}, 123]`,
+			expr:     "$[?length(@) == 3]",
+			want:     `["☺☺☺" [1,2,3]]`,
+		},
+		"function: count": {
+			document: `[{"a": 1, "b": 2}, {"a": 1}]`,
+			expr:     "$[?count(@.*) == 1]",
+			want:     `[{"a":1}]`,
+		},
+		"function: match": {
+			document: `[{"d": "1974-05-01"}, {"d": "1974-05-011"}, {"d": "x"}]`,
+			expr:     `$[?match(@.d, '1974-05-..')]`,
+			want:     `[{"d":"1974-05-01"}]`,
+		},
+		"function: search": {
+			document: `[{"d": "1974-05-01"}, {"d": "1974-05-011"}, {"d": "x"}]`,
+			expr:     `$[?search(@.d, '05-..')]`,
+			want:     `[{"d":"1974-05-01"} {"d":"1974-05-011"}]`,
+		},
+		"function: match dot does not match line feed": {
+			document: `["a\nb", "a b"]`,
+			expr:     `$[?match(@, 'a.b')]`,
+			want:     `["a b"]`,
+		},
+		"function: match pattern from document": {
+			document: `{"p": "[a-c]+", "a": ["abc", "xyz"]}`,
+			expr:     `$.a[?match(@, $.p)]`,
+			want:     `["abc"]`,
+		},
+		"function: match invalid pattern": {
+			document: `["a"]`,
+			expr:     `$[?match(@, '(')]`,
+			want:     "[]",
+		},
+		"function: value": {
+			document: `[{"a": [{"c": "red"}]}, {"a": [{"c": "red"}, {"c": "blue"}]}]`,
+			expr:     `$[?value(@..c) == "red"]`,
+			want:     `[{"a":[{"c":"red"}]}]`,
+		},
+		"function: nested": {
+			document: `[[1, 2], [1, 2, 3]]`,
+			expr:     `$[?length(@) == length($[1])]`,
+			want:     "[[1,2,3]]",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			document, err := node.Parse(tt.document)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.document, err)
+			}
+
+			path, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatalf("Compile(%s) error: %v", tt.expr, err)
+			}
+
+			got := fmt.Sprint(path.Evaluate(document))
+			if got != tt.want {
+				t.Fatalf("Evaluate(%s) = %s, want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_error(t *testing.T) {
+	tests := map[string]struct {
+		expr    string
+		wantErr string
+	}{
+		"empty": {
+			expr:    "",
+			wantErr: `Expected '$' at offset 0: ""`,
+		},
+		"no root": {
+			expr:    "a.b",
+			wantErr: `Expected '$' at offset 0: "a.b"`,
+		},
+		"trailing blank": {
+			expr:    "$.a ",
+			wantErr: `Unexpected character ' ' at offset 3: "$.a "`,
+		},
+		"shorthand starting with digit": {
+			expr:    "$.1",
+			wantErr: `Expected member name or '*' at offset 2: "$.1"`,
+		},
+		"unclosed bracket": {
+			expr:    "$['a'",
+			wantErr: `Expected ',' or ']' at offset 5: "$['a'"`,
+		},
+		"leading zero": {
+			expr:    "$[01]",
+			wantErr: `Invalid integer "01" at offset 2: "$[01]"`,
+		},
+		"negative zero": {
+			expr:    "$[-0]",
+			wantErr: `Invalid integer "-0" at offset 2: "$[-0]"`,
+		},
+		"index out of range": {
+			expr:    "$[9007199254740992]",
+			wantErr: `Integer out of range "9007199254740992" at offset 2: "$[9007199254740992]"`,
+		},
+		"invalid escape": {
+			expr:    `$['\a']`,
+			wantErr: `Invalid escape at offset 3: "$['\\a']"`,
+		},
+		"lone surrogate": {
+			expr:    `$['\uD800']`,
+			wantErr: `Expected low surrogate at offset 3: "$['\\uD800']"`,
+		},
+		"literal as test": {
+			expr:    "$[?true]",
+			wantErr: `Expected comparison at offset 3: "$[?true]"`,
+		},
+		"non-singular query in comparison": {
+			expr:    "$[?@.* == 1]",
+			wantErr: `Expected singular query at offset 3: "$[?@.* == 1]"`,
+		},
+		"length as test": {
+			expr:    "$[?length(@)]",
+			wantErr: `Function length does not return a logical value at offset 3: "$[?length(@)]"`,
+		},
+		"match in comparison": {
+			expr:    "$[?match(@, 'a') == true]",
+			wantErr: `Function match does not return a value at offset 3: "$[?match(@, 'a') == true]"`,
+		},
+		"unknown function": {
+			expr:    "$[?foo(@)]",
+			wantErr: `Unknown function "foo" at offset 3: "$[?foo(@)]"`,
+		},
+		"wrong argument type": {
+			expr:    "$[?count(1) == 1]",
+			wantErr: `Invalid argument type at offset 9: "$[?count(1) == 1]"`,
+		},
+		"too many arguments": {
+			expr:    "$[?length(@, @) == 1]",
+			wantErr: `Too many arguments to length at offset 13: "$[?length(@, @) == 1]"`,
+		},
+		"too few arguments": {
+			expr:    "$[?match(@) == 1]",
+			wantErr: `Too few arguments to match at offset 11: "$[?match(@) == 1]"`,
+		},
+		"unclosed parenthesis": {
+			expr:    "$[?(@.a]",
+			wantErr: `Expected ')' at offset 7: "$[?(@.a]"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile(tt.expr)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Compile(%s) error: %v, want %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
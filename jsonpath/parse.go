@@ -0,0 +1,596 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/a-skua/json-parser/node"
+)
+
+// maxInt is the largest integer allowed in indexes and slices (I-JSON range).
+const maxInt = 1<<53 - 1
+
+type parser struct {
+	expr string
+	pos  int
+}
+
+func (p *parser) parse() (query, error) {
+	if !p.consume("$") {
+		return query{}, p.errorf("Expected '$'")
+	}
+	q, err := p.parseSegments(false)
+	if err != nil {
+		return query{}, err
+	}
+	if p.pos < len(p.expr) {
+		return query{}, p.errorf("Unexpected character %q", p.peekRune())
+	}
+	return q, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Expr: p.expr, Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.expr) {
+		return p.expr[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) peekRune() rune {
+	r, _ := utf8.DecodeRuneInString(p.expr[p.pos:])
+	return r
+}
+
+func (p *parser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipBlank() {
+	for p.pos < len(p.expr) {
+		switch p.expr[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// parseSegments parses segments following '$' or '@'.
+func (p *parser) parseSegments(relative bool) (query, error) {
+	q := query{relative: relative, segments: []segment{}}
+	for {
+		start := p.pos
+		p.skipBlank()
+
+		var s segment
+		var err error
+		switch {
+		case p.consume(".."):
+			s, err = p.parseDescendantSegment()
+		case p.consume("."):
+			s, err = p.parseShorthand()
+		case p.peek() == '[':
+			s.selectors, err = p.parseBracketedSelection()
+		default:
+			p.pos = start
+			return q, nil
+		}
+		if err != nil {
+			return query{}, err
+		}
+		q.segments = append(q.segments, s)
+	}
+}
+
+func (p *parser) parseDescendantSegment() (segment, error) {
+	if p.peek() == '[' {
+		selectors, err := p.parseBracketedSelection()
+		return segment{descendant: true, selectors: selectors}, err
+	}
+	s, err := p.parseShorthand()
+	s.descendant = true
+	return s, err
+}
+
+// parseShorthand parses the wildcard or member name after '.'.
+func (p *parser) parseShorthand() (segment, error) {
+	if p.consume("*") {
+		return segment{selectors: []selector{wildcardSelector{}}}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.expr) {
+		r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+		if !isNameChar(r) || (p.pos == start && isDigit(r)) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return segment{}, p.errorf("Expected member name or '*'")
+	}
+	return segment{selectors: []selector{nameSelector(p.expr[start:p.pos])}}, nil
+}
+
+func isNameChar(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || isDigit(r) || r == '_' ||
+		(0x80 <= r && r <= 0xD7FF) || (0xE000 <= r && r <= 0x10FFFF)
+}
+
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+func (p *parser) parseBracketedSelection() ([]selector, error) {
+	p.pos++ // '['
+	selectors := []selector{}
+	for {
+		p.skipBlank()
+		s, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, s)
+
+		p.skipBlank()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("Expected ',' or ']'")
+		}
+	}
+}
+
+func (p *parser) parseSelector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		return nameSelector(name), err
+	case c == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case c == '?':
+		p.pos++
+		p.skipBlank()
+		expr, err := p.parseLogicalOr()
+		return filterSelector{expr}, err
+	case c == '-' || c == ':' || isDigit(rune(c)):
+		return p.parseIndexOrSlice()
+	default:
+		return nil, p.errorf("Expected selector")
+	}
+}
+
+func (p *parser) parseIndexOrSlice() (selector, error) {
+	var bounds [3]*int
+	for i := 0; i < len(bounds); i++ {
+		if c := p.peek(); c == '-' || isDigit(rune(c)) {
+			n, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			bounds[i] = &n
+		}
+		p.skipBlank()
+
+		if i == 0 && p.peek() != ':' {
+			if bounds[0] == nil {
+				return nil, p.errorf("Expected index")
+			}
+			return indexSelector(*bounds[0]), nil
+		}
+		if i == 2 || !p.consume(":") {
+			break
+		}
+		p.skipBlank()
+	}
+
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	return sliceSelector{start: bounds[0], end: bounds[1], step: step}, nil
+}
+
+// parseInt parses "0" or an optionally negative integer without leading zeros.
+func (p *parser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	for isDigit(rune(p.peek())) {
+		p.pos++
+	}
+
+	literal := p.expr[start:p.pos]
+	if p.pos == digits || (p.expr[digits] == '0' && (p.pos-digits > 1 || digits > start)) {
+		p.pos = start
+		return 0, p.errorf("Invalid integer %q", literal)
+	}
+	n, err := strconv.Atoi(literal)
+	if err != nil || n > maxInt || n < -maxInt {
+		p.pos = start
+		return 0, p.errorf("Integer out of range %q", literal)
+	}
+	return n, nil
+}
+
+// parseString parses a single or double quoted string literal.
+func (p *parser) parseString() (string, error) {
+	quote := p.expr[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.expr) {
+		r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+		switch {
+		case r == rune(quote):
+			p.pos++
+			return b.String(), nil
+		case r == '\\':
+			r, err := p.parseEscape(quote)
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+			continue
+		case r < 0x20:
+			return "", p.errorf("Unexpected control character %U", r)
+		case r == utf8.RuneError && size == 1:
+			return "", p.errorf("Invalid UTF-8")
+		}
+		b.WriteRune(r)
+		p.pos += size
+	}
+	return "", p.errorf("Unterminated string")
+}
+
+func (p *parser) parseEscape(quote byte) (rune, error) {
+	start := p.pos
+	p.pos++ // '\\'
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		return '\b', nil
+	case 'f':
+		return '\f', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case '/', '\\', quote:
+		return rune(c), nil
+	case 'u':
+		r, err := p.parseHex()
+		if err != nil {
+			return 0, err
+		}
+		if 0xDC00 <= r && r <= 0xDFFF {
+			p.pos = start
+			return 0, p.errorf("Unexpected low surrogate")
+		}
+		if 0xD800 <= r && r <= 0xDBFF {
+			if !p.consume(`\u`) {
+				p.pos = start
+				return 0, p.errorf("Expected low surrogate")
+			}
+			r2, err := p.parseHex()
+			if err != nil {
+				return 0, err
+			}
+			if r2 < 0xDC00 || 0xDFFF < r2 {
+				p.pos = start
+				return 0, p.errorf("Expected low surrogate")
+			}
+			r = utf16.DecodeRune(r, r2)
+		}
+		return r, nil
+	default:
+		p.pos = start
+		return 0, p.errorf("Invalid escape")
+	}
+}
+
+func (p *parser) parseHex() (rune, error) {
+	if p.pos+4 > len(p.expr) {
+		return 0, p.errorf("Invalid unicode escape")
+	}
+	n, err := strconv.ParseUint(p.expr[p.pos:p.pos+4], 16, 16)
+	if err != nil {
+		return 0, p.errorf("Invalid unicode escape")
+	}
+	p.pos += 4
+	return rune(n), nil
+}
+
+func (p *parser) parseLogicalOr() (logicalExpr, error) {
+	exprs := orExpr{}
+	for {
+		expr, err := p.parseLogicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		start := p.pos
+		p.skipBlank()
+		if !p.consume("||") {
+			p.pos = start
+			break
+		}
+		p.skipBlank()
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *parser) parseLogicalAnd() (logicalExpr, error) {
+	exprs := andExpr{}
+	for {
+		expr, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		start := p.pos
+		p.skipBlank()
+		if !p.consume("&&") {
+			p.pos = start
+			break
+		}
+		p.skipBlank()
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *parser) parseBasic() (logicalExpr, error) {
+	if p.peek() == '!' && !strings.HasPrefix(p.expr[p.pos:], "!=") {
+		p.pos++
+		p.skipBlank()
+		if p.peek() == '(' {
+			expr, err := p.parseParen()
+			return notExpr{expr}, err
+		}
+		expr, err := p.parseTest()
+		return notExpr{expr}, err
+	}
+	if p.peek() == '(' {
+		return p.parseParen()
+	}
+
+	start := p.pos
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	operandEnd := p.pos
+	p.skipBlank()
+	op := p.parseComparisonOp()
+	if op == "" {
+		p.pos = operandEnd
+		return p.testExpr(left, start)
+	}
+
+	lhs, err := p.comparable(left, start)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	rightStart := p.pos
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := p.comparable(right, rightStart)
+	if err != nil {
+		return nil, err
+	}
+	return comparisonExpr{lhs, op, rhs}, nil
+}
+
+func (p *parser) parseParen() (logicalExpr, error) {
+	p.pos++ // '('
+	p.skipBlank()
+	expr, err := p.parseLogicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if !p.consume(")") {
+		return nil, p.errorf("Expected ')'")
+	}
+	return expr, nil
+}
+
+func (p *parser) parseTest() (logicalExpr, error) {
+	start := p.pos
+	operand, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return p.testExpr(operand, start)
+}
+
+func (p *parser) parseComparisonOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// parseOperand parses a literal, a filter query or a function expression.
+func (p *parser) parseOperand() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '@':
+		p.pos++
+		return p.parseSegments(true)
+	case c == '$':
+		p.pos++
+		return p.parseSegments(false)
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		return literalExpr{node.NewString(s)}, err
+	case c == '-' || isDigit(rune(c)):
+		return p.parseNumber()
+	case 'a' <= c && c <= 'z':
+		return p.parseNameOrLiteral()
+	default:
+		return nil, p.errorf("Expected expression")
+	}
+}
+
+func (p *parser) parseNumber() (literalExpr, error) {
+	start := p.pos
+	for strings.IndexByte("0123456789+-.eE", p.peek()) >= 0 && p.peek() != 0 {
+		p.pos++
+	}
+	n, err := node.NewNumber(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return literalExpr{}, p.errorf("Invalid number %q", p.expr[start:p.pos])
+	}
+	return literalExpr{n}, nil
+}
+
+func (p *parser) parseNameOrLiteral() (interface{}, error) {
+	start := p.pos
+	for c := p.peek(); ('a' <= c && c <= 'z') || isDigit(rune(c)) || c == '_'; c = p.peek() {
+		p.pos++
+	}
+	name := p.expr[start:p.pos]
+
+	if p.peek() == '(' {
+		return p.parseFunction(name, start)
+	}
+	switch name {
+	case "true":
+		return literalExpr{node.NewBoolean(true)}, nil
+	case "false":
+		return literalExpr{node.NewBoolean(false)}, nil
+	case "null":
+		return literalExpr{node.NewNull()}, nil
+	}
+	p.pos = start
+	return nil, p.errorf("Unexpected name %q", name)
+}
+
+func (p *parser) parseFunction(name string, start int) (functionExpr, error) {
+	fn, ok := functions[name]
+	if !ok {
+		p.pos = start
+		return functionExpr{}, p.errorf("Unknown function %q", name)
+	}
+	p.pos++ // '('
+
+	e := functionExpr{name: name, fn: fn, args: []interface{}{}}
+	p.skipBlank()
+	for !p.consume(")") {
+		if len(e.args) > 0 && !p.consume(",") {
+			return functionExpr{}, p.errorf("Expected ',' or ')'")
+		}
+		p.skipBlank()
+		if len(e.args) == len(fn.params) {
+			return functionExpr{}, p.errorf("Too many arguments to %s", name)
+		}
+
+		argStart := p.pos
+		operand, err := p.parseOperand()
+		if err != nil {
+			return functionExpr{}, err
+		}
+		arg, err := p.argument(operand, fn.params[len(e.args)], argStart)
+		if err != nil {
+			return functionExpr{}, err
+		}
+		e.args = append(e.args, arg)
+		p.skipBlank()
+	}
+	if len(e.args) != len(fn.params) {
+		return functionExpr{}, p.errorf("Too few arguments to %s", name)
+	}
+
+	if fn.pattern != 0 {
+		if pattern, ok := e.args[1].(literalExpr); ok {
+			if s, ok := pattern.node.(node.String); ok {
+				e.pattern, _ = compileRegexp(s.Value().(string), fn.pattern == matchFull)
+			}
+		}
+	}
+	return e, nil
+}
+
+// argument checks operand against the declared parameter type.
+func (p *parser) argument(operand interface{}, param paramType, start int) (interface{}, error) {
+	switch param {
+	case nodesType:
+		if q, ok := operand.(query); ok {
+			return q, nil
+		}
+	case valueType:
+		return p.comparable(operand, start)
+	}
+	p.pos = start
+	return nil, p.errorf("Invalid argument type")
+}
+
+// comparable checks operand is of ValueType.
+func (p *parser) comparable(operand interface{}, start int) (valueExpr, error) {
+	switch operand := operand.(type) {
+	case literalExpr:
+		return operand, nil
+	case query:
+		if operand.singular() {
+			return singularQueryExpr{operand}, nil
+		}
+		p.pos = start
+		return nil, p.errorf("Expected singular query")
+	case functionExpr:
+		if operand.fn.result == valueType {
+			return operand, nil
+		}
+		p.pos = start
+		return nil, p.errorf("Function %s does not return a value", operand.name)
+	}
+	p.pos = start
+	return nil, p.errorf("Expected comparable")
+}
+
+// testExpr checks operand is usable as a test expression.
+func (p *parser) testExpr(operand interface{}, start int) (logicalExpr, error) {
+	switch operand := operand.(type) {
+	case query:
+		return existenceExpr{operand}, nil
+	case functionExpr:
+		if operand.fn.result == logicalType {
+			return operand, nil
+		}
+		p.pos = start
+		return nil, p.errorf("Function %s does not return a logical value", operand.name)
+	}
+	p.pos = start
+	return nil, p.errorf("Expected comparison")
+}
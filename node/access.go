@@ -0,0 +1,93 @@
+package node
+
+import (
+	"sync"
+)
+
+// indexThreshold is the number of fields from which Object builds a hash index
+// on the first lookup.
+const indexThreshold = 16
+
+type objectIndex struct {
+	once sync.Once
+	keys map[string]int
+}
+
+func newObject(fields []ObjectField) Object {
+	o := Object{fields: fields}
+	if len(fields) >= indexThreshold {
+		o.index = &objectIndex{}
+	}
+	return o
+}
+
+func (i *objectIndex) lookup(fields []ObjectField, key string) (int, bool) {
+	i.once.Do(func() {
+		i.keys = make(map[string]int, len(fields))
+		for j, field := range fields {
+			i.keys[field.Key] = j
+		}
+	})
+	j, ok := i.keys[key]
+	return j, ok
+}
+
+// Get returns the value of key. For duplicate keys the last value wins.
+func (o Object) Get(key string) (Node, bool) {
+	if i, ok := o.find(key); ok {
+		return o.fields[i].Value, true
+	}
+	return nil, false
+}
+
+func (o Object) Has(key string) bool {
+	_, ok := o.find(key)
+	return ok
+}
+
+// Keys returns the keys in source order.
+func (o Object) Keys() []string {
+	keys := make([]string, len(o.fields))
+	for i, field := range o.fields {
+		keys[i] = field.Key
+	}
+	return keys
+}
+
+// Fields returns a copy of the fields in source order, duplicates included.
+func (o Object) Fields() []ObjectField {
+	return append([]ObjectField(nil), o.fields...)
+}
+
+func (o Object) Len() int {
+	return len(o.fields)
+}
+
+func (o Object) find(key string) (int, bool) {
+	if o.index != nil {
+		return o.index.lookup(o.fields, key)
+	}
+	for i := len(o.fields) - 1; i >= 0; i-- {
+		if o.fields[i].Key == key {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Nodes returns a copy of the elements.
+func (a Array) Nodes() []Node {
+	return append([]Node(nil), a.nodes...)
+}
+
+func (a Array) Len() int {
+	return len(a.nodes)
+}
+
+// At returns the i-th node, or false if i is out of range.
+func (a Array) At(i int) (Node, bool) {
+	if i < 0 || len(a.nodes) <= i {
+		return nil, false
+	}
+	return a.nodes[i], true
+}
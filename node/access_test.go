@@ -0,0 +1,162 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestObject_Get(t *testing.T) {
+	large := make([]string, 0, indexThreshold*2)
+	for i := 0; i < indexThreshold*2; i++ {
+		large = append(large, fmt.Sprintf(`"key%d": %d`, i, i))
+	}
+
+	tests := map[string]struct {
+		input    string
+		key      string
+		want     string
+		wantOk   bool
+		wantKeys []string
+	}{
+		"found": {
+			input:    `{"a": 1, "b": [true]}`,
+			key:      "b",
+			want:     "[true]",
+			wantOk:   true,
+			wantKeys: []string{"a", "b"},
+		},
+		"not found": {
+			input:    `{"a": 1}`,
+			key:      "b",
+			wantKeys: []string{"a"},
+		},
+		"escaped key": {
+			input:    `{"a\nb": 1}`,
+			key:      "a\nb",
+			want:     "1",
+			wantOk:   true,
+			wantKeys: []string{"a\nb"},
+		},
+		"duplicate keys": {
+			input:    `{"a": 1, "b": 2, "a": 3}`,
+			key:      "a",
+			want:     "3",
+			wantOk:   true,
+			wantKeys: []string{"a", "b", "a"},
+		},
+		"empty": {
+			input:    `{}`,
+			key:      "",
+			wantKeys: []string{},
+		},
+		"indexed": {
+			input:  "{" + strings.Join(large, ",") + `, "key3": "last"}`,
+			key:    "key3",
+			want:   `"last"`,
+			wantOk: true,
+		},
+		"indexed: not found": {
+			input: "{" + strings.Join(large, ",") + "}",
+			key:   "key",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			node, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%s) error: %v", tt.input, err)
+			}
+			o := node.(Object)
+
+			for i := 0; i < 2; i++ {
+				got, ok := o.Get(tt.key)
+				if ok != tt.wantOk || (ok && got.String() != tt.want) {
+					t.Fatalf("Object.Get(%s) = %v, %v, want %v, %v", tt.key, got, ok, tt.want, tt.wantOk)
+				}
+				if has := o.Has(tt.key); has != tt.wantOk {
+					t.Fatalf("Object.Has(%s) = %v, want %v", tt.key, has, tt.wantOk)
+				}
+			}
+
+			if tt.wantKeys == nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantKeys, o.Keys()); diff != "" {
+				t.Fatalf("Object.Keys() mismatch (-want +got):\n%s", diff)
+			}
+			if o.Len() != len(tt.wantKeys) {
+				t.Fatalf("Object.Len() = %d, want %d", o.Len(), len(tt.wantKeys))
+			}
+		})
+	}
+}
+
+func TestArray_At(t *testing.T) {
+	a := NewArray(NewString("a"), NewNumberFromInt(1))
+
+	tests := map[string]struct {
+		index  int
+		want   string
+		wantOk bool
+	}{
+		"first": {
+			index:  0,
+			want:   `"a"`,
+			wantOk: true,
+		},
+		"last": {
+			index:  1,
+			want:   "1",
+			wantOk: true,
+		},
+		"negative": {
+			index: -1,
+		},
+		"out of range": {
+			index: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := a.At(tt.index)
+			if ok != tt.wantOk || (ok && got.String() != tt.want) {
+				t.Fatalf("Array.At(%d) = %v, %v, want %v, %v", tt.index, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	if a.Len() != 2 {
+		t.Fatalf("Array.Len() = %d, want 2", a.Len())
+	}
+}
+
+func TestObject_Fields(t *testing.T) {
+	o := NewObject(ObjectField{"a", NewNumberFromInt(1)}, ObjectField{"a", NewString("b")})
+
+	fields := o.Fields()
+	if got := NewObject(fields...).String(); got != `{"a":1,"a":"b"}` {
+		t.Fatalf("Object.Fields() = %s, want %s", got, `{"a":1,"a":"b"}`)
+	}
+	fields[0] = ObjectField{"c", Null{}}
+	if got := o.String(); got != `{"a":1,"a":"b"}` {
+		t.Fatalf("Object.Fields() shares storage: %s", got)
+	}
+}
+
+func TestArray_Nodes(t *testing.T) {
+	a := NewArray(NewString("a"), NewNumberFromInt(1))
+
+	nodes := a.Nodes()
+	if got := NewArray(nodes...).String(); got != `["a",1]` {
+		t.Fatalf("Array.Nodes() = %s, want %s", got, `["a",1]`)
+	}
+	nodes[0] = Null{}
+	if got := a.String(); got != `["a",1]` {
+		t.Fatalf("Array.Nodes() shares storage: %s", got)
+	}
+}
@@ -0,0 +1,206 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Builder builds a document with chained calls, e.g.
+//
+//	NewBuilder().StartObject().Key("a").Int(1).EndObject().Build()
+//
+// The first misuse is kept and returned by Build.
+type Builder struct {
+	stack []frame
+	root  Node
+	err   error
+}
+
+type frame struct {
+	object bool
+	nodes  []Node
+	fields []ObjectField
+	key    *string
+}
+
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+func (b *Builder) StartObject() *Builder {
+	if b.expectValue() {
+		b.stack = append(b.stack, frame{object: true, fields: make([]ObjectField, 0)})
+	}
+	return b
+}
+
+func (b *Builder) EndObject() *Builder {
+	if f, ok := b.pop(true); ok {
+		b.add(newObject(f.fields))
+	}
+	return b
+}
+
+func (b *Builder) StartArray() *Builder {
+	if b.expectValue() {
+		b.stack = append(b.stack, frame{nodes: make([]Node, 0)})
+	}
+	return b
+}
+
+func (b *Builder) EndArray() *Builder {
+	if f, ok := b.pop(false); ok {
+		b.add(Array{f.nodes})
+	}
+	return b
+}
+
+func (b *Builder) Key(key string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if len(b.stack) == 0 || !b.top().object {
+		b.err = fmt.Errorf("Unexpected key outside of object: %s", Quote(key, 0))
+		return b
+	}
+	if b.top().key != nil {
+		b.err = fmt.Errorf("Unexpected key after key: %s", Quote(key, 0))
+		return b
+	}
+	b.top().key = &key
+	return b
+}
+
+func (b *Builder) String(s string) *Builder {
+	return b.Value(NewString(s))
+}
+
+func (b *Builder) Int(i int64) *Builder {
+	return b.Value(NewNumberFromInt(i))
+}
+
+func (b *Builder) Uint(u uint64) *Builder {
+	return b.Value(NewNumberFromUint(u))
+}
+
+func (b *Builder) Float(f float64) *Builder {
+	n, err := NewNumberFromFloat(f)
+	if err != nil && b.err == nil {
+		b.err = err
+	}
+	return b.Value(n)
+}
+
+func (b *Builder) Bool(v bool) *Builder {
+	return b.Value(NewBoolean(v))
+}
+
+func (b *Builder) Null() *Builder {
+	return b.Value(NewNull())
+}
+
+// Value adds a node built elsewhere. Nodes of types other than those of this
+// package are rejected, as their output is not known to be valid.
+func (b *Builder) Value(node Node) *Builder {
+	if !b.expectValue() {
+		return b
+	}
+	if err := checkNode(orNull(node)); err != nil {
+		b.err = err
+		return b
+	}
+	b.add(orNull(node))
+	return b
+}
+
+func checkNode(node Node) error {
+	switch node := node.(type) {
+	case Object:
+		for _, field := range node.fields {
+			if err := checkNode(field.Value); err != nil {
+				return err
+			}
+		}
+	case Array:
+		for _, n := range node.nodes {
+			if err := checkNode(n); err != nil {
+				return err
+			}
+		}
+	case String, Number, Boolean, Null:
+	default:
+		return fmt.Errorf("Unsupported node type: %T", node)
+	}
+	return nil
+}
+
+// Build returns the document, or the first error.
+func (b *Builder) Build() (Node, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.stack) > 0 {
+		return nil, errors.New("Unexpected end of document: unclosed object or array")
+	}
+	if b.root == nil {
+		return nil, errors.New("Unexpected end of document: no value")
+	}
+	return b.root, nil
+}
+
+func (b *Builder) top() *frame {
+	return &b.stack[len(b.stack)-1]
+}
+
+// expectValue reports whether a value may be added.
+func (b *Builder) expectValue() bool {
+	switch {
+	case b.err != nil:
+		return false
+	case len(b.stack) == 0 && b.root != nil:
+		b.err = errors.New("Unexpected value after the end of document")
+		return false
+	case len(b.stack) > 0 && b.top().object && b.top().key == nil:
+		b.err = errors.New("Unexpected value without key in object")
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *Builder) add(node Node) {
+	if len(b.stack) == 0 {
+		b.root = node
+		return
+	}
+
+	f := b.top()
+	if f.object {
+		f.fields = append(f.fields, ObjectField{*f.key, node})
+		f.key = nil
+		return
+	}
+	f.nodes = append(f.nodes, node)
+}
+
+func (b *Builder) pop(object bool) (frame, bool) {
+	if b.err != nil {
+		return frame{}, false
+	}
+	if len(b.stack) == 0 || b.top().object != object {
+		if object {
+			b.err = errors.New("Unexpected end of object")
+		} else {
+			b.err = errors.New("Unexpected end of array")
+		}
+		return frame{}, false
+	}
+	if b.top().key != nil {
+		b.err = fmt.Errorf("Unexpected end of object after key: %s", Quote(*b.top().key, 0))
+		return frame{}, false
+	}
+
+	f := *b.top()
+	b.stack = b.stack[:len(b.stack)-1]
+	return f, true
+}
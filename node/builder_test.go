@@ -0,0 +1,115 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+// foreignNode is a Node implemented outside of the node types.
+type foreignNode struct{}
+
+func (foreignNode) Type() Type         { return TypeNumber }
+func (foreignNode) Value() interface{} { return nil }
+func (foreignNode) String() string     { return "" }
+
+func TestBuilder(t *testing.T) {
+	tests := map[string]struct {
+		build   func(b *Builder) *Builder
+		want    string
+		wantErr string
+	}{
+		"scalar": {
+			build: func(b *Builder) *Builder {
+				return b.String("hello")
+			},
+			want: `"hello"`,
+		},
+		"nested": {
+			build: func(b *Builder) *Builder {
+				return b.StartObject().
+					Key("name").String("a\"b").
+					Key("numbers").StartArray().Int(-1).Uint(2).Float(0.5).EndArray().
+					Key("flags").StartArray().Bool(true).Bool(false).Null().EndArray().
+					Key("empty").StartObject().EndObject().
+					Key("node").Value(NewArray(NewString("x"), nil)).
+					EndObject()
+			},
+			want: `{"name":"a\"b","numbers":[-1,2,0.5],"flags":[true,false,null],"empty":{},"node":["x",null]}`,
+		},
+		"no value": {
+			build: func(b *Builder) *Builder {
+				return b
+			},
+			wantErr: "Unexpected end of document: no value",
+		},
+		"multiple values": {
+			build: func(b *Builder) *Builder {
+				return b.Int(1).Int(2)
+			},
+			wantErr: "Unexpected value after the end of document",
+		},
+		"unclosed": {
+			build: func(b *Builder) *Builder {
+				return b.StartArray().Int(1)
+			},
+			wantErr: "Unexpected end of document: unclosed object or array",
+		},
+		"mismatched end": {
+			build: func(b *Builder) *Builder {
+				return b.StartArray().EndObject()
+			},
+			wantErr: "Unexpected end of object",
+		},
+		"key outside of object": {
+			build: func(b *Builder) *Builder {
+				return b.StartArray().Key("a")
+			},
+			wantErr: `Unexpected key outside of object: "a"`,
+		},
+		"value without key": {
+			build: func(b *Builder) *Builder {
+				return b.StartObject().Int(1)
+			},
+			wantErr: "Unexpected value without key in object",
+		},
+		"key without value": {
+			build: func(b *Builder) *Builder {
+				return b.StartObject().Key("a").EndObject()
+			},
+			wantErr: `Unexpected end of object after key: "a"`,
+		},
+		"zero number": {
+			build: func(b *Builder) *Builder {
+				return b.StartArray().Value(Number{}).EndArray()
+			},
+			want: `[0]`,
+		},
+		"foreign node": {
+			build: func(b *Builder) *Builder {
+				return b.StartArray().Value(NewObject(ObjectField{"a", foreignNode{}})).EndArray()
+			},
+			wantErr: "Unsupported node type: node.foreignNode",
+		},
+		"first error is kept": {
+			build: func(b *Builder) *Builder {
+				return b.StartArray().Float(math.NaN()).EndObject()
+			},
+			wantErr: "Invalid number: NaN",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			node, err := tt.build(NewBuilder()).Build()
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Build() error: %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("Build() error: nil, wantErr %v", tt.wantErr)
+			}
+			if err == nil && node.String() != tt.want {
+				t.Fatalf("Build() = %v, want %v", node, tt.want)
+			}
+		})
+	}
+}
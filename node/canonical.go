@@ -0,0 +1,121 @@
+package node
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Canonicalize returns n in the JSON Canonicalization Scheme (RFC 8785).
+func Canonicalize(n Node) ([]byte, error) {
+	return AppendCanonical(nil, n)
+}
+
+// AppendCanonical appends the JCS form of n to dst. Object keys are sorted by
+// UTF-16 code units and numbers are written as ECMAScript does. Duplicate
+// keys and numbers out of the range of float64 are reported as errors.
+func AppendCanonical(dst []byte, n Node) ([]byte, error) {
+	switch n := n.(type) {
+	case Object:
+		fields := slices.Clone(n.fields)
+		slices.SortStableFunc(fields, func(a, b ObjectField) int {
+			return compareUTF16(a.Key, b.Key)
+		})
+
+		dst = append(dst, '{')
+		for i, field := range fields {
+			if i > 0 && fields[i-1].Key == field.Key {
+				return nil, fmt.Errorf("Duplicate key: %s", Quote(field.Key, 0))
+			}
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = AppendQuote(dst, field.Key, 0)
+			dst = append(dst, ':')
+
+			var err error
+			if dst, err = AppendCanonical(dst, field.Value); err != nil {
+				return nil, err
+			}
+		}
+		return append(dst, '}'), nil
+
+	case Array:
+		dst = append(dst, '[')
+		for i, node := range n.nodes {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+
+			var err error
+			if dst, err = AppendCanonical(dst, node); err != nil {
+				return nil, err
+			}
+		}
+		return append(dst, ']'), nil
+
+	case Number:
+		f, err := n.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return appendECMAScript(dst, f), nil
+
+	case String:
+		return AppendQuote(dst, n.value, 0), nil
+
+	default:
+		return append(dst, n.String()...), nil
+	}
+}
+
+func compareUTF16(a, b string) int {
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
+
+// appendECMAScript appends f as Number.prototype.toString does.
+func appendECMAScript(dst []byte, f float64) []byte {
+	if f == 0 {
+		return append(dst, '0')
+	}
+	if f < 0 {
+		dst = append(dst, '-')
+		f = -f
+	}
+
+	// The shortest digits that round-trip, and the position n of the
+	// decimal point relative to them.
+	e := strconv.AppendFloat(nil, f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(string(e), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	k := len(digits)
+	n, _ := strconv.Atoi(exp)
+	n++
+
+	switch {
+	case k <= n && n <= 21:
+		dst = append(dst, digits...)
+		return append(dst, strings.Repeat("0", n-k)...)
+	case 0 < n && n <= 21:
+		dst = append(dst, digits[:n]...)
+		dst = append(dst, '.')
+		return append(dst, digits[n:]...)
+	case -6 < n && n <= 0:
+		dst = append(dst, "0."...)
+		dst = append(dst, strings.Repeat("0", -n)...)
+		return append(dst, digits...)
+	}
+
+	dst = append(dst, digits[0])
+	if k > 1 {
+		dst = append(dst, '.')
+		dst = append(dst, digits[1:]...)
+	}
+	dst = append(dst, 'e')
+	if n-1 > 0 {
+		dst = append(dst, '+')
+	}
+	return strconv.AppendInt(dst, int64(n-1), 10)
+}
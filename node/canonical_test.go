@@ -0,0 +1,116 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		// RFC 8785 section 3.2.2.
+		"example": {
+			input: `{
+				"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+				"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+				"literals": [null, true, false]
+			}`,
+			want: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		// RFC 8785 section 3.2.3.
+		"sorting": {
+			input: `{
+				"\u20ac": "Euro Sign",
+				"\r": "Carriage Return",
+				"\ufb33": "Hebrew Letter Dalet With Dagesh",
+				"1": "One",
+				"\ud83d\ude00": "Emoji: Grinning Face",
+				"\u0080": "Control",
+				"\u00f6": "Latin Small Letter O With Diaeresis"
+			}`,
+			want: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001F600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		"nested": {
+			input: `[{"b": {"d": 1, "c": 2}, "a": []}, {}]`,
+			want:  `[{"a":[],"b":{"c":2,"d":1}},{}]`,
+		},
+		"no extra escapes": {
+			input: `"<&>\u2028\u007f"`,
+			want:  "\"<&>\u2028\u007f\"",
+		},
+		"integers lose precision": {
+			input: `[9007199254740993, -0, 1.0e2]`,
+			want:  `[9007199254740992,0,100]`,
+		},
+		"duplicate keys": {
+			input:   `{"a": 1, "b": 2, "a": 3}`,
+			wantErr: `Duplicate key: "a"`,
+		},
+		"out of range": {
+			input:   `[1e400]`,
+			wantErr: "Cannot convert 1e400 to float64: value out of range",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := Parse(tt.input, NumberAs(RepresentationLiteral))
+			if err != nil {
+				t.Fatalf("Parse(%s) error: %v", tt.input, err)
+			}
+
+			got, err := Canonicalize(n)
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Canonicalize(%s) error: %v (want: %v)", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("Canonicalize(%s) error: nil (want: %v)", tt.input, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("Canonicalize(%s) = %s (want: %s)", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendECMAScript(t *testing.T) {
+	// RFC 8785 appendix B.
+	tests := map[uint64]string{
+		0x0000000000000000: "0",
+		0x8000000000000000: "0",
+		0x0000000000000001: "5e-324",
+		0x8000000000000001: "-5e-324",
+		0x7fefffffffffffff: "1.7976931348623157e+308",
+		0xffefffffffffffff: "-1.7976931348623157e+308",
+		0x4340000000000000: "9007199254740992",
+		0xc340000000000000: "-9007199254740992",
+		0x4430000000000000: "295147905179352830000",
+		0x44b52d02c7e14af5: "9.999999999999997e+22",
+		0x44b52d02c7e14af6: "1e+23",
+		0x44b52d02c7e14af7: "1.0000000000000001e+23",
+		0x44b52d02c7e14af8: "1.0000000000000003e+23",
+		0x444b1ae4d6e2ef4e: "999999999999999700000",
+		0x444b1ae4d6e2ef4f: "999999999999999900000",
+		0x444b1ae4d6e2ef50: "1e+21",
+		0x3eb0c6f7a0b5ed8d: "0.000001",
+		0x3eb0c6f7a0b5ed8e: "0.0000010000000000000002",
+		0x3eb0c6f7a0b5ed8c: "9.999999999999997e-7",
+		0x41b3de4355555553: "333333333.3333332",
+		0x41b3de4355555554: "333333333.33333325",
+		0x41b3de4355555555: "333333333.3333333",
+		0x41b3de4355555556: "333333333.3333334",
+		0x41b3de4355555557: "333333333.33333343",
+		0xbecbf647612f3696: "-0.0000033333333333333333",
+		0x43143ff3c1cb0959: "1424953923781206.2",
+	}
+
+	for bits, want := range tests {
+		f := math.Float64frombits(bits)
+		if got := string(appendECMAScript(nil, f)); got != want {
+			t.Errorf("appendECMAScript(%#x) = %s (want: %s)", bits, got, want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package node
+
+import (
+	"bufio"
+	"io"
+)
+
+// WriteCompact writes n to w without insignificant whitespace, as String
+// returns it with no escape. Keys and strings are quoted with escape, as
+// Quote does. Writers other than *bufio.Writer, *bytes.Buffer and
+// *strings.Builder are buffered; a *bufio.Writer is left unflushed.
+func WriteCompact(w io.Writer, n Node, escape Escape) error {
+	e := newEncoder(w, escape)
+	e.compact(n)
+	return e.flush()
+}
+
+// writer is implemented by *bufio.Writer, *bytes.Buffer and *strings.Builder.
+type writer interface {
+	io.Writer
+	io.ByteWriter
+	io.StringWriter
+}
+
+// encoder writes to w and keeps the first error.
+type encoder struct {
+	w      writer
+	buf    *bufio.Writer // set if the encoder buffers w itself
+	err    error
+	escape Escape
+
+	scratch []byte
+}
+
+func newEncoder(w io.Writer, escape Escape) *encoder {
+	if w, ok := w.(writer); ok {
+		return &encoder{w: w, escape: escape}
+	}
+	buf := bufio.NewWriter(w)
+	return &encoder{w: buf, buf: buf, escape: escape}
+}
+
+func (e *encoder) writeByte(c byte) {
+	if e.err == nil {
+		e.err = e.w.WriteByte(c)
+	}
+}
+
+func (e *encoder) writeString(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+func (e *encoder) writeQuote(s string) {
+	e.scratch = AppendQuote(e.scratch[:0], s, e.escape)
+	if e.err == nil {
+		_, e.err = e.w.Write(e.scratch)
+	}
+}
+
+// writeScalar writes n, which is neither an object nor an array.
+func (e *encoder) writeScalar(n Node) {
+	if s, ok := n.(String); ok {
+		e.writeQuote(s.value)
+		return
+	}
+	e.writeString(n.String())
+}
+
+// scalar returns n as writeScalar writes it.
+func (e *encoder) scalar(n Node) string {
+	if s, ok := n.(String); ok {
+		return Quote(s.value, e.escape)
+	}
+	return n.String()
+}
+
+func (e *encoder) flush() error {
+	if e.err == nil && e.buf != nil {
+		e.err = e.buf.Flush()
+	}
+	return e.err
+}
+
+func (e *encoder) compact(n Node) {
+	switch n := n.(type) {
+	case Object:
+		e.writeByte('{')
+		for i, field := range n.fields {
+			if i > 0 {
+				e.writeByte(',')
+			}
+			e.writeQuote(field.Key)
+			e.writeByte(':')
+			e.compact(field.Value)
+		}
+		e.writeByte('}')
+	case Array:
+		e.writeByte('[')
+		for i, node := range n.nodes {
+			if i > 0 {
+				e.writeByte(',')
+			}
+			e.compact(node)
+		}
+		e.writeByte(']')
+	default:
+		e.writeScalar(n)
+	}
+}
@@ -0,0 +1,97 @@
+package node
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteCompact(t *testing.T) {
+	tests := map[string]struct {
+		input  string
+		node   Node
+		escape Escape
+		want   string
+	}{
+		"scalars": {
+			input: `[ "aA\n" , 1.50 , true , false , null ]`,
+			want:  `["aA\n",1.50,true,false,null]`,
+		},
+		"nested": {
+			input: `{ "a" : { "b" : [ ] } , "c\"" : { } }`,
+			want:  `{"a":{"b":[]},"c\"":{}}`,
+		},
+		"duplicate keys": {
+			input: `{"a": 1, "a": 2}`,
+			want:  `{"a":1,"a":2}`,
+		},
+		"escape": {
+			input:  `{"<a>": ["é\u2028&", 1]}`,
+			escape: EscapeASCII | EscapeHTML | EscapeLineTerminators,
+			want:   `{"\u003ca\u003e":["\u00e9\u2028\u0026",1]}`,
+		},
+		"zero number": {
+			node: NewArray(Number{}, NewObject(ObjectField{"n", Number{}})),
+			want: `[0,{"n":0}]`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := tt.node
+			if n == nil {
+				var err error
+				n, err = Parse(tt.input)
+				if err != nil {
+					t.Fatalf("Parse(%s) error: %v", tt.input, err)
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := WriteCompact(&buf, n, tt.escape); err != nil {
+				t.Fatalf("WriteCompact(%s) error: %v", tt.input, err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("WriteCompact(%s) = %s (want: %s)", tt.input, got, tt.want)
+			}
+			if got := n.String(); tt.escape == 0 && got != tt.want {
+				t.Fatalf("String() = %s (want: %s)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCompact_writers(t *testing.T) {
+	nodes := make([]Node, 10000)
+	for i := range nodes {
+		nodes[i] = NewString("value")
+	}
+	n := NewArray(nodes...)
+	want := "[" + strings.Repeat(`"value",`, len(nodes)-1) + `"value"]`
+
+	var unbuffered strings.Builder
+	if err := WriteCompact(struct{ io.Writer }{&unbuffered}, n, 0); err != nil {
+		t.Fatalf("WriteCompact() error: %v", err)
+	}
+	if got := unbuffered.String(); got != want {
+		t.Fatalf("WriteCompact() to io.Writer wrote %d bytes (want: %d)", len(got), len(want))
+	}
+
+	var buffered bytes.Buffer
+	w := bufio.NewWriter(&buffered)
+	if err := WriteCompact(w, n, 0); err != nil {
+		t.Fatalf("WriteCompact() error: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+	if got := buffered.String(); got != want {
+		t.Fatalf("WriteCompact() to *bufio.Writer wrote %d bytes (want: %d)", len(got), len(want))
+	}
+
+	if err := WriteCompact(failingWriter{}, n, 0); err == nil || err.Error() != "Write failed" {
+		t.Fatalf("WriteCompact() error: %v (want: Write failed)", err)
+	}
+}
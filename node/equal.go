@@ -0,0 +1,205 @@
+package node
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	fieldOrder     bool
+	numberLiterals bool
+}
+
+// FieldOrder makes objects equal only if their members, duplicates included,
+// appear in the same order.
+func FieldOrder() EqualOption {
+	return func(o *equalOptions) {
+		o.fieldOrder = true
+	}
+}
+
+// NumberLiterals makes numbers equal only if they are written the same way,
+// so that 1 and 1.0 differ.
+func NumberLiterals() EqualOption {
+	return func(o *equalOptions) {
+		o.numberLiterals = true
+	}
+}
+
+func newEqualOptions(opts []EqualOption) equalOptions {
+	o := equalOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Equal reports whether a and b are the same JSON value.
+// By default member order is ignored, the last of duplicate keys wins,
+// and numbers are compared by their exact decimal value.
+func Equal(a, b Node, opts ...EqualOption) bool {
+	return newEqualOptions(opts).equal(a, b)
+}
+
+func (o equalOptions) equal(a, b Node) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a := a.(type) {
+	case Object:
+		b := b.(Object)
+		if o.fieldOrder {
+			if len(a.fields) != len(b.fields) {
+				return false
+			}
+			for i, field := range a.fields {
+				if field.Key != b.fields[i].Key || !o.equal(field.Value, b.fields[i].Value) {
+					return false
+				}
+			}
+			return true
+		}
+		fields := a.members()
+		if len(fields) != len(b.members()) {
+			return false
+		}
+		for _, field := range fields {
+			value, ok := b.Get(field.Key)
+			if !ok || !o.equal(field.Value, value) {
+				return false
+			}
+		}
+		return true
+	case Array:
+		b := b.(Array)
+		if len(a.nodes) != len(b.nodes) {
+			return false
+		}
+		for i, node := range a.nodes {
+			if !o.equal(node, b.nodes[i]) {
+				return false
+			}
+		}
+		return true
+	case Number:
+		return o.number(a) == o.number(b.(Number))
+	case String, Boolean, Null:
+		return a.Value() == b.Value()
+	default:
+		return a.String() == b.String()
+	}
+}
+
+// members returns the fields of o without the overridden duplicates.
+func (o Object) members() []ObjectField {
+	fields := make([]ObjectField, 0, len(o.fields))
+	for i, field := range o.fields {
+		if j, _ := o.find(field.Key); i == j {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func (o equalOptions) number(n Number) string {
+	if o.numberLiterals {
+		return n.Literal()
+	}
+	return n.canonical()
+}
+
+// canonical returns the number as "±0.DIGITSeEXP" without insignificant
+// zeros, so that equal numbers have equal forms however large the exponent.
+func (n Number) canonical() string {
+	literal := n.Literal()
+	neg := strings.HasPrefix(literal, "-")
+	literal = strings.TrimPrefix(literal, "-")
+
+	exp := new(big.Int)
+	if i := strings.IndexAny(literal, "eE"); i >= 0 {
+		exp.SetString(strings.TrimPrefix(literal[i+1:], "+"), 10)
+		literal = literal[:i]
+	}
+	point := len(literal)
+	if i := strings.IndexByte(literal, '.'); i >= 0 {
+		point = i
+		literal = literal[:i] + literal[i+1:]
+	}
+
+	digits := strings.TrimLeft(literal, "0")
+	point -= len(literal) - len(digits)
+	digits = strings.TrimRight(digits, "0")
+	if digits == "" {
+		return "0"
+	}
+
+	sign := "+"
+	if neg {
+		sign = "-"
+	}
+	return sign + "0." + digits + "e" + exp.Add(exp, big.NewInt(int64(point))).String()
+}
+
+// Hash returns a hash of n that is stable across processes and equal for
+// nodes that are Equal with the same options.
+func Hash(n Node, opts ...EqualOption) uint64 {
+	h := fnv.New64a()
+	newEqualOptions(opts).hash(h, n)
+	return h.Sum64()
+}
+
+func (o equalOptions) hash(h hash.Hash64, n Node) {
+	h.Write([]byte{byte(n.Type())})
+
+	switch n := n.(type) {
+	case Object:
+		if o.fieldOrder {
+			writeLength(h, len(n.fields))
+			for _, field := range n.fields {
+				writeString(h, field.Key)
+				o.hash(h, field.Value)
+			}
+			return
+		}
+		fields := n.members()
+		sums := make([]uint64, len(fields))
+		for i, field := range fields {
+			member := fnv.New64a()
+			writeString(member, field.Key)
+			o.hash(member, field.Value)
+			sums[i] = member.Sum64()
+		}
+		slices.Sort(sums)
+		writeLength(h, len(sums))
+		for _, sum := range sums {
+			h.Write(binary.BigEndian.AppendUint64(nil, sum))
+		}
+	case Array:
+		writeLength(h, len(n.nodes))
+		for _, node := range n.nodes {
+			o.hash(h, node)
+		}
+	case Number:
+		writeString(h, o.number(n))
+	case String:
+		writeString(h, n.value)
+	default:
+		writeString(h, n.String())
+	}
+}
+
+func writeLength(h hash.Hash64, n int) {
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(n)))
+}
+
+func writeString(h hash.Hash64, s string) {
+	writeLength(h, len(s))
+	h.Write([]byte(s))
+}
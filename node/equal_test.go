@@ -0,0 +1,140 @@
+package node
+
+import (
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	tests := map[string]struct {
+		a    string
+		b    string
+		opts []EqualOption
+		want bool
+	}{
+		"scalars": {
+			a:    `[true, false, null, "a"]`,
+			b:    `[true, false, null, "a"]`,
+			want: true,
+		},
+		"different types": {
+			a:    `1`,
+			b:    `"1"`,
+			want: false,
+		},
+		"escaped strings": {
+			a:    `"é\n"`,
+			b:    `"é\u000a"`,
+			want: true,
+		},
+		"numbers": {
+			a:    `[1, 100, 0.5, -0, 1e400]`,
+			b:    `[1.0, 1e2, 5e-1, 0.0, 10e399]`,
+			want: true,
+		},
+		"large numbers": {
+			a:    `123456789012345678901234567890`,
+			b:    `123456789012345678901234567891`,
+			want: false,
+		},
+		"huge exponents": {
+			a:    `1e100000000000000000000`,
+			b:    `0.1e100000000000000000001`,
+			want: true,
+		},
+		"number literals": {
+			a:    `1`,
+			b:    `1.0`,
+			opts: []EqualOption{NumberLiterals()},
+			want: false,
+		},
+		"member order": {
+			a:    `{"a": 1, "b": [1, 2]}`,
+			b:    `{"b": [1, 2], "a": 1}`,
+			want: true,
+		},
+		"field order": {
+			a:    `{"a": 1, "b": 2}`,
+			b:    `{"b": 2, "a": 1}`,
+			opts: []EqualOption{FieldOrder()},
+			want: false,
+		},
+		"field order: same": {
+			a:    `{"a": 1, "b": {"c": 2.0}}`,
+			b:    `{"a": 1, "b": {"c": 2}}`,
+			opts: []EqualOption{FieldOrder()},
+			want: true,
+		},
+		"array order": {
+			a:    `[1, 2]`,
+			b:    `[2, 1]`,
+			want: false,
+		},
+		"missing member": {
+			a:    `{"a": 1, "b": 2}`,
+			b:    `{"a": 1, "c": 2}`,
+			want: false,
+		},
+		"duplicate keys": {
+			a:    `{"a": 1, "b": 2, "a": 3}`,
+			b:    `{"b": 2, "a": 3}`,
+			want: true,
+		},
+		"duplicate keys: field order": {
+			a:    `{"a": 1, "b": 2, "a": 3}`,
+			b:    `{"b": 2, "a": 3}`,
+			opts: []EqualOption{FieldOrder()},
+			want: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, err := Parse(tt.a, NumberAs(RepresentationLiteral))
+			if err != nil {
+				t.Fatalf("Parse(%s) error: %v", tt.a, err)
+			}
+			b, err := Parse(tt.b, NumberAs(RepresentationLiteral))
+			if err != nil {
+				t.Fatalf("Parse(%s) error: %v", tt.b, err)
+			}
+
+			if got := Equal(a, b, tt.opts...); got != tt.want {
+				t.Fatalf("Equal(%s, %s) = %v (want: %v)", tt.a, tt.b, got, tt.want)
+			}
+			if got := Equal(b, a, tt.opts...); got != tt.want {
+				t.Fatalf("Equal(%s, %s) = %v (want: %v)", tt.b, tt.a, got, tt.want)
+			}
+			if got := Hash(a, tt.opts...) == Hash(b, tt.opts...); got != tt.want {
+				t.Fatalf("Hash(%s) == Hash(%s) = %v (want: %v)", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  uint64
+	}{
+		"null": {
+			input: `null`,
+			want:  0xf7003966ce8a5924,
+		},
+		"object": {
+			input: `{"a": [1, "b", true]}`,
+			want:  0x827b18ac16908ce3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%s) error: %v", tt.input, err)
+			}
+			if got := Hash(n); got != tt.want {
+				t.Fatalf("Hash(%s) = %#x (want: %#x)", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package node
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// field is a struct field mapped to an object member by its `json` tag:
+//
+//	Field int `json:"name,omitempty,string"`
+//
+// A tag of "-" skips the field. Fields of embedded structs are promoted
+// unless the embedded field is named by its tag.
+type field struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+}
+
+type structFields struct {
+	list   []field
+	byName map[string]int
+}
+
+var fieldCache sync.Map // map[reflect.Type]structFields
+
+func cachedFields(t reflect.Type) structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(structFields)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.(structFields)
+}
+
+// lookup returns the field named key, or else one whose name matches it
+// case-insensitively.
+func (s structFields) lookup(key string) (field, bool) {
+	if i, ok := s.byName[key]; ok {
+		return s.list[i], true
+	}
+	for _, f := range s.list {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+func typeFields(t reflect.Type) structFields {
+	type candidate struct {
+		field
+		depth int
+	}
+	candidates := []candidate{}
+
+	visiting := map[reflect.Type]bool{t: true}
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if sf.Anonymous {
+				if !sf.IsExported() && ft.Kind() != reflect.Struct {
+					continue
+				}
+				if name == "" && ft.Kind() == reflect.Struct {
+					if !visiting[ft] {
+						visiting[ft] = true
+						walk(ft, append(slices.Clip(index), i))
+						delete(visiting, ft)
+					}
+					continue
+				}
+			} else if !sf.IsExported() {
+				continue
+			}
+
+			f := field{
+				name:   name,
+				index:  append(slices.Clip(index), i),
+				typ:    sf.Type,
+				tagged: name != "",
+			}
+			if f.name == "" {
+				f.name = sf.Name
+			}
+			for _, opt := range strings.Split(opts, ",") {
+				switch opt {
+				case "omitempty":
+					f.omitEmpty = true
+				case "string":
+					f.quoted = quotable(ft)
+				}
+			}
+			candidates = append(candidates, candidate{f, len(index)})
+		}
+	}
+	walk(t, nil)
+
+	// Of the fields with the same name, the shallowest wins, then the only
+	// tagged one at that depth. Other conflicts hide the name altogether.
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if a.depth != b.depth {
+			return a.depth - b.depth
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+	fields := []field{}
+	for i := 0; i < len(candidates); {
+		j := i + 1
+		for j < len(candidates) && candidates[j].name == candidates[i].name {
+			j++
+		}
+		group := candidates[i:j]
+		dominant := len(group) == 1 ||
+			group[1].depth > group[0].depth ||
+			(group[0].tagged && !group[1].tagged)
+		if dominant {
+			fields = append(fields, group[0].field)
+		}
+		i = j
+	}
+
+	slices.SortFunc(fields, func(a, b field) int {
+		return slices.Compare(a.index, b.index)
+	})
+	byName := make(map[string]int, len(fields))
+	for i, f := range fields {
+		byName[f.name] = i
+	}
+	return structFields{fields, byName}
+}
+
+// quotable reports whether the "string" option applies to values of t.
+func quotable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
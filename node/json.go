@@ -0,0 +1,108 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ToAny returns n as the value encoding/json decodes with UseNumber:
+// map[string]any, []any, string, json.Number, bool or nil. Of duplicate
+// keys, the last wins.
+func ToAny(n Node) any {
+	switch n := n.(type) {
+	case Object:
+		m := make(map[string]any, len(n.fields))
+		for _, field := range n.fields {
+			m[field.Key] = ToAny(field.Value)
+		}
+		return m
+	case Array:
+		s := make([]any, len(n.nodes))
+		for i, node := range n.nodes {
+			s[i] = ToAny(node)
+		}
+		return s
+	case String:
+		return n.value
+	case Number:
+		return json.Number(n.Literal())
+	case Boolean:
+		return n.value
+	default:
+		return nil
+	}
+}
+
+func (s String) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (n Number) MarshalJSON() ([]byte, error) {
+	return marshalJSON(n)
+}
+
+func (b Boolean) MarshalJSON() ([]byte, error) {
+	return marshalJSON(b)
+}
+
+func (n Null) MarshalJSON() ([]byte, error) {
+	return marshalJSON(n)
+}
+
+func (a Array) MarshalJSON() ([]byte, error) {
+	return marshalJSON(a)
+}
+
+func (o Object) MarshalJSON() ([]byte, error) {
+	return marshalJSON(o)
+}
+
+func marshalJSON(n Node) ([]byte, error) {
+	var b bytes.Buffer
+	err := WriteCompact(&b, n, 0)
+	return b.Bytes(), err
+}
+
+func (s *String) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+func (n *Number) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, n)
+}
+
+func (b *Boolean) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, b)
+}
+
+func (n *Null) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, n)
+}
+
+func (a *Array) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, a)
+}
+
+func (o *Object) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, o)
+}
+
+// unmarshalJSON parses b into *dst, keeping number literals. Null leaves
+// *dst unchanged, as encoding/json does for other types.
+func unmarshalJSON[T Node](b []byte, dst *T) error {
+	n, err := ParseBytes(b, NumberAs(RepresentationLiteral))
+	if err != nil {
+		return err
+	}
+	if _, ok := n.(Null); ok {
+		return nil
+	}
+	v, ok := n.(T)
+	if !ok {
+		return fmt.Errorf("Cannot unmarshal %s into %s", n.Type(), reflect.TypeFor[T]())
+	}
+	*dst = v
+	return nil
+}
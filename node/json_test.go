@@ -0,0 +1,168 @@
+package node
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestToAny(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  any
+	}{
+		"object": {
+			input: `{"a": 1, "b": [true, null, "s"], "a": 1e400}`,
+			want: map[string]any{
+				"a": json.Number("1e400"),
+				"b": []any{true, nil, "s"},
+			},
+		},
+		"number": {
+			input: `-0.10`,
+			want:  json.Number("-0.10"),
+		},
+		"null": {
+			input: `null`,
+			want:  nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := Parse(tt.input, NumberAs(RepresentationLiteral))
+			if err != nil {
+				t.Fatalf("Parse(%s) error: %v", tt.input, err)
+			}
+			if diff := cmp.Diff(tt.want, ToAny(n)); diff != "" {
+				t.Fatalf("ToAny(%s) mismatch (-want +got):\n%s", tt.input, diff)
+			}
+		})
+	}
+}
+
+func TestFromValue_encodingJSON(t *testing.T) {
+	tests := map[string]struct {
+		input any
+		want  string
+	}{
+		"any": {
+			input: map[string]any{
+				"a": []any{json.Number("1e400"), "s", false, nil},
+				"b": map[string]any{},
+			},
+			want: `{"a":[1e400,"s",false,null],"b":{}}`,
+		},
+		"raw message": {
+			input: struct {
+				Raw   json.RawMessage `json:"raw"`
+				Empty json.RawMessage `json:"empty"`
+			}{json.RawMessage(`{"x": [1, 2]}`), nil},
+			want: `{"raw":{"x":[1,2]},"empty":null}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := FromValue(tt.input)
+			if err != nil {
+				t.Fatalf("FromValue() error: %v", err)
+			}
+			if got := n.String(); got != tt.want {
+				t.Fatalf("FromValue() = %s (want: %s)", got, tt.want)
+			}
+		})
+	}
+
+	_, err := FromValue(json.Number("01"))
+	if want := "Cannot marshal json.Number at root: Invalid number: '01'"; err == nil || err.Error() != want {
+		t.Fatalf("FromValue(01) error: %v (want: %s)", err, want)
+	}
+}
+
+func TestUnmarshal_encodingJSON(t *testing.T) {
+	var v struct {
+		Number json.Number     `json:"number"`
+		Raw    json.RawMessage `json:"raw"`
+	}
+	input := `{"number": 1e400, "raw": {"x": [1, 2]}}`
+	if err := Decode(input, &v, NumberAs(RepresentationLiteral)); err != nil {
+		t.Fatalf("Decode(%s) error: %v", input, err)
+	}
+	if v.Number != "1e400" || string(v.Raw) != `{"x":[1,2]}` {
+		t.Fatalf("Decode(%s) = %+v", input, v)
+	}
+
+	var nodes jsonEmbedding
+	input = `{"object": [1], "string": null}`
+	want := "Cannot unmarshal array into node.Object at /object"
+	if err := Decode(input, &nodes); err == nil || err.Error() != want {
+		t.Fatalf("Decode(%s) error: %v (want: %s)", input, err, want)
+	}
+}
+
+type jsonEmbedding struct {
+	Object Object  `json:"object"`
+	Array  Array   `json:"array"`
+	String String  `json:"string"`
+	Number Number  `json:"number"`
+	Bool   Boolean `json:"bool"`
+	Null   Null    `json:"null"`
+	Ptr    *Object `json:"ptr"`
+}
+
+func TestNode_encodingJSON(t *testing.T) {
+	input := `{"object":{"a":1e400,"a":"x"},"array":[1,{}],"string":"é","number":-0.0,"bool":true,"null":null,"ptr":{"b":[]}}`
+
+	var v jsonEmbedding
+	if err := json.Unmarshal([]byte(input), &v); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", input, err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if want := `{"object":{"a":1e400,"a":"x"},"array":[1,{}],"string":"é","number":-0.0,"bool":true,"null":null,"ptr":{"b":[]}}`; string(b) != want {
+		t.Fatalf("json.Marshal() = %s (want: %s)", b, want)
+	}
+
+	b, err = json.Marshal(jsonEmbedding{})
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if want := `{"object":{},"array":[],"string":"","number":0,"bool":false,"null":null,"ptr":null}`; string(b) != want {
+		t.Fatalf("json.Marshal() = %s (want: %s)", b, want)
+	}
+}
+
+func TestNode_encodingJSON_errors(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"type mismatch": {
+			input:   `{"object": [1]}`,
+			wantErr: "Cannot unmarshal array into node.Object",
+		},
+		"null keeps value": {
+			input: `{"object": null, "string": null}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var v jsonEmbedding
+			err := json.Unmarshal([]byte(tt.input), &v)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("json.Unmarshal(%s) error: %v", tt.input, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("json.Unmarshal(%s) error: %v (want: %s)", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
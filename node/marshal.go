@@ -0,0 +1,326 @@
+package node
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Marshaler is implemented by types that convert themselves to a node.
+type Marshaler interface {
+	MarshalNode() (Node, error)
+}
+
+// MarshalError reports a Go value that cannot be converted to a node.
+// Path is the JSON Pointer the value would have had.
+type MarshalError struct {
+	Path string
+	Type reflect.Type
+	Err  error
+}
+
+func (e *MarshalError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "root"
+	}
+	return fmt.Sprintf("Cannot marshal %s at %s: %v", e.Type, path, e.Err)
+}
+
+func (e *MarshalError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	marshalerType     = reflect.TypeFor[Marshaler]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	jsonNumberType    = reflect.TypeFor[json.Number]()
+)
+
+// FromValue converts v to a node by the same rules Unmarshal reads it back.
+//
+// Structs become objects by the `json` tags of their fields, with
+// "omitempty" leaving out false, 0, "", nil and empty values, and "string"
+// writing scalars as strings. Maps become objects sorted by key, []byte a
+// base64 string, json.Number a number, and nil pointers, interfaces, maps
+// and slices null. Nodes are returned as they are.
+//
+// Types implementing Marshaler, json.Marshaler or encoding.TextMarshaler
+// convert themselves, tried in that order; time.Time becomes an RFC 3339
+// string this way.
+func FromValue(v any) (Node, error) {
+	e := valueEncoder{visiting: map[any]bool{}}
+	return e.value(reflect.ValueOf(v))
+}
+
+type valueEncoder struct {
+	path     []string
+	visiting map[any]bool
+}
+
+func (e *valueEncoder) error(t reflect.Type, format string, args ...any) error {
+	return &MarshalError{pointerString(e.path), t, fmt.Errorf(format, args...)}
+}
+
+func (e *valueEncoder) value(v reflect.Value) (Node, error) {
+	if !v.IsValid() {
+		return Null{}, nil
+	}
+	if v.Kind() != reflect.Interface && v.Kind() != reflect.Pointer && v.Type().Implements(nodeType) {
+		return v.Interface().(Node), nil
+	}
+	if n, ok, err := e.hook(v); ok {
+		return n, err
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return NewBoolean(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewNumberFromInt(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return NewNumberFromUint(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, e.error(v.Type(), "Invalid number: %v", f)
+		}
+		literal := strconv.FormatFloat(f, 'g', -1, v.Type().Bits())
+		f, _ = strconv.ParseFloat(literal, 64)
+		return Number{literal, f}, nil
+	case reflect.String:
+		if v.Type() == jsonNumberType {
+			n, err := parseNumber(v.String(), RepresentationLiteral)
+			if err != nil {
+				return nil, e.error(v.Type(), "%w", err)
+			}
+			return n, nil
+		}
+		return NewString(v.String()), nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return Null{}, nil
+		}
+		return e.value(v.Elem())
+	case reflect.Pointer:
+		if v.IsNil() {
+			return Null{}, nil
+		}
+		return e.visit(v, func() (Node, error) {
+			return e.value(v.Elem())
+		})
+	case reflect.Struct:
+		return e.structValue(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return Null{}, nil
+		}
+		return e.visit(v, func() (Node, error) {
+			return e.mapValue(v)
+		})
+	case reflect.Slice:
+		if v.IsNil() {
+			return Null{}, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return NewString(base64.StdEncoding.EncodeToString(v.Bytes())), nil
+		}
+		return e.visit(v, func() (Node, error) {
+			return e.arrayValue(v)
+		})
+	case reflect.Array:
+		return e.arrayValue(v)
+	default:
+		return nil, e.error(v.Type(), "Unsupported type")
+	}
+}
+
+// hook converts v with the first of Marshaler, json.Marshaler and
+// encoding.TextMarshaler it implements, and reports whether there was one.
+func (e *valueEncoder) hook(v reflect.Value) (Node, bool, error) {
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, false, nil
+	}
+
+	var i any
+	switch t := v.Type(); {
+	case t.Implements(marshalerType) || t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType):
+		i = v.Interface()
+	case v.CanAddr():
+		i = v.Addr().Interface()
+	}
+
+	switch m := i.(type) {
+	case Marshaler:
+		n, err := m.MarshalNode()
+		if err != nil {
+			return nil, true, e.error(v.Type(), "%w", err)
+		}
+		return orNull(n), true, nil
+	case json.Marshaler:
+		b, err := m.MarshalJSON()
+		if err != nil {
+			return nil, true, e.error(v.Type(), "%w", err)
+		}
+		n, err := ParseBytes(b, NumberAs(RepresentationLiteral))
+		if err != nil {
+			return nil, true, e.error(v.Type(), "%w", err)
+		}
+		return n, true, nil
+	case encoding.TextMarshaler:
+		b, err := m.MarshalText()
+		if err != nil {
+			return nil, true, e.error(v.Type(), "%w", err)
+		}
+		return NewString(string(b)), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+// visit detects cycles through the pointer of v.
+func (e *valueEncoder) visit(v reflect.Value, f func() (Node, error)) (Node, error) {
+	key := struct {
+		ptr any
+		len int
+	}{v.UnsafePointer(), 0}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	if e.visiting[key] {
+		return nil, e.error(v.Type(), "Encountered a cycle")
+	}
+	e.visiting[key] = true
+	defer delete(e.visiting, key)
+	return f()
+}
+
+func (e *valueEncoder) structValue(v reflect.Value) (Node, error) {
+	fields := []ObjectField{}
+	for _, f := range cachedFields(v.Type()).list {
+		fv, ok := fieldByIndexIfSet(v, f.index)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+
+		e.path = append(e.path, f.name)
+		n, err := e.value(fv)
+		if err == nil && f.quoted {
+			n, err = quote(n)
+		}
+		e.path = e.path[:len(e.path)-1]
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, ObjectField{f.name, n})
+	}
+	return newObject(fields), nil
+}
+
+// fieldByIndexIfSet returns the field of v at index, or false if it is
+// inside a nil embedded pointer.
+func fieldByIndexIfSet(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// quote writes a scalar as a string for the "string" option.
+func quote(n Node) (Node, error) {
+	switch n.(type) {
+	case Object, Array, Null:
+		return n, nil
+	default:
+		return NewString(n.String()), nil
+	}
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+func (e *valueEncoder) mapValue(v reflect.Value) (Node, error) {
+	fields := make([]ObjectField, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := e.mapKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		e.path = append(e.path, key)
+		n, err := e.value(iter.Value())
+		e.path = e.path[:len(e.path)-1]
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, ObjectField{key, n})
+	}
+	slices.SortFunc(fields, func(a, b ObjectField) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return newObject(fields), nil
+}
+
+func (e *valueEncoder) mapKey(k reflect.Value) (string, error) {
+	if m, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		b, err := m.MarshalText()
+		if err != nil {
+			return "", e.error(k.Type(), "%w", err)
+		}
+		return string(b), nil
+	}
+
+	switch k.Kind() {
+	case reflect.String:
+		return k.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	default:
+		return "", e.error(k.Type(), "Unsupported map key type")
+	}
+}
+
+func (e *valueEncoder) arrayValue(v reflect.Value) (Node, error) {
+	nodes := make([]Node, v.Len())
+	for i := range nodes {
+		e.path = append(e.path, strconv.Itoa(i))
+		n, err := e.value(v.Index(i))
+		e.path = e.path[:len(e.path)-1]
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = n
+	}
+	return Array{nodes}, nil
+}
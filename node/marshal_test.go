@@ -0,0 +1,191 @@
+package node
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type marshalCycle struct {
+	Next *marshalCycle `json:"next"`
+}
+
+type marshalEmpty struct {
+	Bool    bool           `json:"bool,omitempty"`
+	Int     int            `json:"int,omitempty"`
+	String  string         `json:"string,omitempty"`
+	Pointer *int           `json:"pointer,omitempty"`
+	Slice   []int          `json:"slice,omitempty"`
+	Map     map[string]int `json:"map,omitempty"`
+	Any     any            `json:"any,omitempty"`
+	Struct  struct{}       `json:"struct,omitempty"`
+	Time    time.Time      `json:"time,omitempty"`
+}
+
+type marshalEmbedded struct {
+	*UnmarshalEmbedded
+	Own int
+}
+
+func TestFromValue(t *testing.T) {
+	tests := map[string]struct {
+		input   any
+		want    string
+		wantErr string
+	}{
+		"nil": {
+			input: nil,
+			want:  `null`,
+		},
+		"scalars": {
+			input: []any{true, "a\"", int8(-8), uint64(math.MaxUint64), 1.5, float32(0.1), 1e21},
+			want:  `[true,"a\"",-8,18446744073709551615,1.5,0.1,1e+21]`,
+		},
+		"nil values": {
+			input: []any{(*int)(nil), []int(nil), map[string]int(nil), []byte(nil)},
+			want:  `[null,null,null,null]`,
+		},
+		"empty values": {
+			input: []any{[]int{}, map[string]int{}, [0]int{}},
+			want:  `[[],{},[]]`,
+		},
+		"bytes": {
+			input: []byte("hello"),
+			want:  `"aGVsbG8="`,
+		},
+		"time": {
+			input: time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC),
+			want:  `"2024-01-02T03:04:05.0000006Z"`,
+		},
+		"map keys are sorted": {
+			input: map[int]string{10: "b", -1: "a", 2: "c"},
+			want:  `{"-1":"a","10":"b","2":"c"}`,
+		},
+		"nodes": {
+			input: map[string]Node{"a": NewArray(NewNull()), "b": nil},
+			want:  `{"a":[null],"b":null}`,
+		},
+		"struct": {
+			input: &unmarshalStruct{
+				Name:     "a",
+				Quoted:   123,
+				QuotedS:  "q",
+				Skipped:  1,
+				Dash:     2,
+				Pointer:  &unmarshalInner{3},
+				Slice:    []unmarshalInner{{4}},
+				Array:    [2]int{5, 6},
+				IntMap:   map[int]bool{7: true},
+				Any:      map[string]any{"b": []any{1, "c"}},
+				Bytes:    []byte("x"),
+				Float:    0.25,
+				Node:     NewString("n"),
+				Object:   NewObject(ObjectField{"o", NewBoolean(false)}),
+				Untagged: true,
+				private:  8,
+				UnmarshalEmbedded: UnmarshalEmbedded{
+					E:      "e",
+					Hidden: "promoted",
+				},
+				unmarshalEmbedded: unmarshalEmbedded{9},
+				Hidden:            "outer",
+			},
+			want: `{"name":"a","quoted":"123","quoted_s":"\"q\"","-":2,"pointer":{"b":3},"slice":[{"b":4}],"array":[5,6],"map":null,"int_map":{"7":true},"any":{"b":[1,"c"]},"bytes":"eA==","float":0.25,"uint":0,"node":"n","object":{"o":false},"Untagged":true,"E":"e","hidden":"promoted","U":9,"Hidden":"outer"}`,
+		},
+		"omitempty": {
+			input: marshalEmpty{Any: (*int)(nil)},
+			want:  `{"any":null,"struct":{},"time":"0001-01-01T00:00:00Z"}`,
+		},
+		"nil embedded pointer": {
+			input: marshalEmbedded{Own: 1},
+			want:  `{"Own":1}`,
+		},
+		"embedded pointer": {
+			input: marshalEmbedded{UnmarshalEmbedded: &UnmarshalEmbedded{E: "e"}},
+			want:  `{"E":"e","hidden":"","Own":0}`,
+		},
+		"NaN": {
+			input:   map[string]any{"a": []float64{math.NaN()}},
+			wantErr: "Cannot marshal float64 at /a/0: Invalid number: NaN",
+		},
+		"unsupported type": {
+			input:   struct{ C chan int }{},
+			wantErr: "Cannot marshal chan int at /C: Unsupported type",
+		},
+		"unsupported map key": {
+			input:   map[float64]int{1: 1},
+			wantErr: "Cannot marshal float64 at root: Unsupported map key type",
+		},
+		"cycle": {
+			input: func() any {
+				c := &marshalCycle{}
+				c.Next = &marshalCycle{c}
+				return c
+			}(),
+			wantErr: "Cannot marshal *node.marshalCycle at /next/next: Encountered a cycle",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := FromValue(tt.input)
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("FromValue(%#v) error: %v (want: %v)", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("FromValue(%#v) error: nil (want: %v)", tt.input, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if s := got.String(); s != tt.want {
+				t.Fatalf("FromValue(%#v) = %s (want: %s)", tt.input, s, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromValue_roundTrip(t *testing.T) {
+	want := unmarshalStruct{
+		Name:     "a",
+		Renamed:  1,
+		Quoted:   -2,
+		QuotedS:  "s",
+		Pointer:  &unmarshalInner{3},
+		Slice:    []unmarshalInner{{4}},
+		Array:    [2]int{5, 6},
+		Map:      map[string]int{"x": 7},
+		IntMap:   map[int]bool{8: true},
+		Any:      []any{"b", 9.5, nil},
+		Bytes:    []byte{0, 1, 2},
+		Float:    0.1,
+		Uint:     10,
+		Untagged: true,
+	}
+
+	n, err := FromValue(want)
+	if err != nil {
+		t.Fatalf("FromValue() error: %v", err)
+	}
+	var got unmarshalStruct
+	if err := Unmarshal(n, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) error: %v", n, err)
+	}
+	got.Node = nil
+	got.Object = Object{}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(unmarshalStruct{}, Object{})); diff != "" {
+		t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFromValue_errors(t *testing.T) {
+	_, err := FromValue([]any{func() {}})
+	var marshalErr *MarshalError
+	if !errors.As(err, &marshalErr) || marshalErr.Path != "/0" {
+		t.Fatalf("FromValue() error: %v (want: *MarshalError at /0)", err)
+	}
+}
@@ -3,7 +3,8 @@ package node
 import (
 	"errors"
 	"fmt"
-	"strconv"
+	"io"
+	"strings"
 
 	"github.com/a-skua/json-parser/node/internal/state"
 	"github.com/a-skua/json-parser/token"
@@ -15,6 +16,8 @@ var (
 	ErrEOO     = errors.New("End of Object")
 	ErrIsComma = errors.New("Token is comma")
 	ErrIsColon = errors.New("Token is colon")
+
+	ErrNotInteger = errors.New("Not an integer")
 )
 
 type Type uint8
@@ -29,6 +32,25 @@ const (
 	TypeNull
 )
 
+func (t Type) String() string {
+	switch t {
+	case TypeObject:
+		return "object"
+	case TypeArray:
+		return "array"
+	case TypeString:
+		return "string"
+	case TypeNumber:
+		return "number"
+	case TypeBoolean:
+		return "boolean"
+	case TypeNull:
+		return "null"
+	default:
+		return "unknown"
+	}
+}
+
 type Node interface {
 	Type() Type
 	Value() interface{}
@@ -37,10 +59,15 @@ type Node interface {
 
 type String struct {
 	value string
+	raw   string
 }
 
 func newString(t token.Token) String {
-	return String{t.Value[1 : len(t.Value)-1]}
+	return String{unquote(t.Value), t.Value}
+}
+
+func NewString(s string) String {
+	return String{s, Quote(s, 0)}
 }
 
 func (s String) Type() Type {
@@ -51,17 +78,30 @@ func (s String) Value() interface{} {
 	return s.value
 }
 
-func (s String) String() string {
-	return "\"" + s.value + "\""
+// Raw returns the string as written in the source, including quotes and escapes.
+func (s String) Raw() string {
+	return s.raw
 }
 
-type Number struct {
-	value float64
+func (s String) String() string {
+	return Quote(s.value, 0)
 }
 
-func newNumber(t token.Token) (Number, error) {
-	value, err := strconv.ParseFloat(t.Value, 64)
-	return Number{value}, err
+type Number struct {
+	literal string
+	value   interface{}
+}
+
+// newNumber converts t to r. Without a representation chosen, it converts
+// to float64 and leaves range errors to Float64.
+func newNumber(t token.Token, r Representation) (Number, error) {
+	n := Number{literal: t.Value}
+	value, err := n.as(r)
+	n.value = value
+	if r == 0 {
+		return n, nil
+	}
+	return n, err
 }
 
 func (n Number) Type() Type {
@@ -69,18 +109,71 @@ func (n Number) Type() Type {
 }
 
 func (n Number) Value() interface{} {
+	if n.literal == "" {
+		return float64(0)
+	}
 	return n.value
 }
 
 func (n Number) String() string {
-	return strconv.FormatFloat(n.value, 'g', -1, 64)
+	return n.Literal()
+}
+
+func Lex(input string, opts ...Option) ([]Node, error) {
+	o := newOptions(opts)
+	return lex(NewLexer(token.NewTokenizer(token.Runes(input), o.tokenizer...), opts...))
+}
+
+// LexReader is like Lex but streams its input from r.
+func LexReader(r io.Reader, opts ...Option) ([]Node, error) {
+	o := newOptions(opts)
+	return lex(NewLexer(token.NewReaderTokenizer(r, o.tokenizer...), opts...))
+}
+
+// LexBytes is like Lex but reads UTF-8 input without converting it to runes.
+func LexBytes(input []byte, opts ...Option) ([]Node, error) {
+	o := newOptions(opts)
+	return lex(NewByteLexer(token.NewByteTokenizer(input, o.tokenizer...), opts...))
+}
+
+// Parse parses input as a single JSON document:
+// exactly one value, optionally surrounded by whitespace.
+// Numbers out of the range of float64 are parsed with Value ±Inf unless
+// NumberAs chooses a representation.
+func Parse(input string, opts ...Option) (Node, error) {
+	o := newOptions(opts)
+	return parse(NewLexer(token.NewTokenizer(token.Runes(input), o.tokenizer...), opts...))
+}
+
+// ParseReader is like Parse but streams its input from r.
+func ParseReader(r io.Reader, opts ...Option) (Node, error) {
+	o := newOptions(opts)
+	return parse(NewLexer(token.NewReaderTokenizer(r, o.tokenizer...), opts...))
+}
+
+// ParseBytes is like Parse but reads UTF-8 input without converting it to runes.
+func ParseBytes(input []byte, opts ...Option) (Node, error) {
+	o := newOptions(opts)
+	return parse(NewByteLexer(token.NewByteTokenizer(input, o.tokenizer...), opts...))
 }
 
-func Lex(input string) ([]Node, error) {
+func parse(lexer Lexer) (Node, error) {
+	node, err := lexer.Next()
+	if err != nil {
+		return nil, lexer.syntaxError(err, valueTypes...)
+	}
+
+	if err := lexer.end(); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+func lex(lexer Lexer) ([]Node, error) {
 	var err error
 	nodes := make([]Node, 0)
 
-	lexer := NewLexer(token.NewTokenizer([]rune(input)))
 	for {
 		var node Node
 		node, err = lexer.Next()
@@ -105,6 +198,10 @@ func newBoolean(t token.Token) Boolean {
 	return Boolean{t.Type == token.True}
 }
 
+func NewBoolean(b bool) Boolean {
+	return Boolean{b}
+}
+
 func (b Boolean) Type() Type {
 	return TypeBoolean
 }
@@ -119,6 +216,10 @@ func newNull() Null {
 	return Null{}
 }
 
+func NewNull() Null {
+	return Null{}
+}
+
 func (n Null) Type() Type {
 	return TypeNull
 }
@@ -143,6 +244,15 @@ type Array struct {
 	nodes []Node
 }
 
+// NewArray returns an Array of nodes. Nil nodes are stored as Null.
+func NewArray(nodes ...Node) Array {
+	a := Array{make([]Node, len(nodes))}
+	for i, node := range nodes {
+		a.nodes[i] = orNull(node)
+	}
+	return a
+}
+
 func (a Array) Type() Type {
 	return TypeArray
 }
@@ -152,22 +262,45 @@ func (a Array) Value() interface{} {
 }
 
 func (a Array) String() string {
-	str := "["
-	for i, node := range a.nodes {
-		if i > 0 {
-			str += ","
-		}
-		str += node.String()
-	}
-	return str + "]"
+	var b strings.Builder
+	WriteCompact(&b, a, 0)
+	return b.String()
+}
+
+type tokenizer interface {
+	Next() (token.Token, error)
+	Pos() token.Position
 }
 
 type Lexer struct {
-	tokenizer token.Tokenizer
+	tokenizer tokenizer
+	last      token.Token
+	opts      options
 }
 
-func NewLexer(tokenizer token.Tokenizer) Lexer {
-	return Lexer{tokenizer: tokenizer}
+func NewLexer(tokenizer token.Tokenizer, opts ...Option) Lexer {
+	return Lexer{tokenizer: &tokenizer, opts: newOptions(opts)}
+}
+
+func NewByteLexer(tokenizer token.ByteTokenizer, opts ...Option) Lexer {
+	return Lexer{tokenizer: byteTokenizer{&tokenizer}, opts: newOptions(opts)}
+}
+
+// byteTokenizer adapts token.ByteTokenizer to the Lexer,
+// copying only the values of tokens the Lexer reads.
+type byteTokenizer struct {
+	*token.ByteTokenizer
+}
+
+func (t byteTokenizer) Next() (token.Token, error) {
+	bt, err := t.ByteTokenizer.Next()
+	if err != nil {
+		return token.Token{}, err
+	}
+	if bt.Type == token.Whitespace {
+		return token.Token{Type: bt.Type, Start: bt.Start, End: bt.End}, nil
+	}
+	return bt.Token(), nil
 }
 
 type ObjectField struct {
@@ -177,6 +310,23 @@ type ObjectField struct {
 
 type Object struct {
 	fields []ObjectField
+	index  *objectIndex
+}
+
+// NewObject returns an Object of fields in order. Nil values are stored as Null.
+func NewObject(fields ...ObjectField) Object {
+	copied := make([]ObjectField, len(fields))
+	for i, field := range fields {
+		copied[i] = ObjectField{field.Key, orNull(field.Value)}
+	}
+	return newObject(copied)
+}
+
+func orNull(node Node) Node {
+	if node == nil {
+		return Null{}
+	}
+	return node
 }
 
 func (o Object) Type() Type {
@@ -188,25 +338,21 @@ func (o Object) Value() interface{} {
 }
 
 func (o Object) String() string {
-	str := "{"
-	for i, field := range o.fields {
-		if i > 0 {
-			str += ","
-		}
-		str += "\"" + field.Key + "\":" + field.Value.String()
-	}
-	return str + "}"
+	var b strings.Builder
+	WriteCompact(&b, o, 0)
+	return b.String()
 }
 
 func (l *Lexer) Next() (Node, error) {
 	var t token.Token
 	var err error
 	for t, err = l.tokenizer.Next(); err == nil; t, err = l.tokenizer.Next() {
+		l.last = t
 		switch t.Type {
 		case token.String:
 			return newString(t), nil
 		case token.Number:
-			return newNumber(t)
+			return newNumber(t, l.opts.number)
 		case token.True, token.False:
 			return newBoolean(t), nil
 		case token.Null:
@@ -214,11 +360,19 @@ func (l *Lexer) Next() (Node, error) {
 		case token.Whitespace:
 			continue
 		case token.LeftBracket:
-			return l.parseArray()
+			node, err := l.parseArray()
+			if err == nil {
+				l.last = t
+			}
+			return node, err
 		case token.RightBracket:
 			return nil, ErrEOA
 		case token.LeftBrace:
-			return l.parseObject()
+			node, err := l.parseObject()
+			if err == nil {
+				l.last = t
+			}
+			return node, err
 		case token.RightBrace:
 			return nil, ErrEOO
 		case token.Comma:
@@ -237,31 +391,75 @@ func (l *Lexer) Next() (Node, error) {
 	return nil, err
 }
 
+var valueTypes = []token.Type{
+	token.String,
+	token.Number,
+	token.True,
+	token.False,
+	token.Null,
+	token.LeftBrace,
+	token.LeftBracket,
+}
+
+// syntaxError converts the result of Lexer.Next into a *token.SyntaxError
+// positioned at the last token read.
+func (l *Lexer) syntaxError(err error, expected ...token.Type) error {
+	switch err {
+	case ErrEON:
+		return &token.SyntaxError{
+			Kind:     token.UnexpectedEnd,
+			Pos:      l.tokenizer.Pos(),
+			Expected: expected,
+		}
+	case nil, ErrEOA, ErrEOO, ErrIsComma, ErrIsColon:
+		return &token.SyntaxError{
+			Kind:     token.UnexpectedToken,
+			Pos:      l.last.Start,
+			Token:    l.last,
+			Expected: expected,
+		}
+	default:
+		return err
+	}
+}
+
+// end reports an error unless only whitespace remains.
+func (l *Lexer) end() error {
+	for {
+		t, err := l.tokenizer.Next()
+		if err == token.ErrEOT {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if t.Type != token.Whitespace {
+			l.last = t
+			return l.syntaxError(nil)
+		}
+	}
+}
+
 func (l *Lexer) parseArray() (Node, error) {
 	nodes := make([]Node, 0)
 	for state := state.NewArray(); ; state = state.Next() {
 		node, err := l.Next()
-		if state.IsSeparator() && err == ErrIsComma {
-			continue
-		}
-
 		if state.IsSeparator() && err == ErrEOA {
 			break
 		}
 
-		if err == ErrEOA {
-			return nil, errors.New("Unexpected End of Array")
+		if state.IsValue() && err != nil {
+			return nil, l.syntaxError(err, valueTypes...)
 		}
 
-		if err == ErrIsComma {
-			return nil, errors.New("Unexpected Comma")
+		if state.IsValue() {
+			nodes = append(nodes, node)
+			continue
 		}
 
-		if err != nil {
-			return nil, err
+		if err != ErrIsComma {
+			return nil, l.syntaxError(err, token.Comma, token.RightBracket)
 		}
-
-		nodes = append(nodes, node)
 	}
 
 	return Array{nodes}, nil
@@ -274,28 +472,37 @@ func (l *Lexer) parseObject() (Node, error) {
 		if state.IsSeparator() && err == ErrIsComma {
 			continue
 		}
-		if err == ErrEOO {
+		if state.IsSeparator() && err == ErrEOO {
+			break
+		}
+		if state.IsSeparator() {
+			return nil, l.syntaxError(err, token.Comma, token.RightBrace)
+		}
+		if len(fields) == 0 && err == ErrEOO {
 			break
 		}
 
-		if !state.IsKey() || key.Type() != TypeString {
-			return nil, fmt.Errorf("Unexpected Token: %v", key)
+		if err != nil || key.Type() != TypeString {
+			if len(fields) == 0 {
+				return nil, l.syntaxError(err, token.String, token.RightBrace)
+			}
+			return nil, l.syntaxError(err, token.String)
 		}
 		state = state.Next()
 
-		colon, err := l.Next()
-		if !state.IsColon() || err != ErrIsColon {
-			return nil, fmt.Errorf("Unexpected Token: %v", colon)
+		_, err = l.Next()
+		if err != ErrIsColon {
+			return nil, l.syntaxError(err, token.Colon)
 		}
 		state = state.Next()
 
 		value, err := l.Next()
-		if !state.IsValue() || err != nil {
-			return nil, err
+		if err != nil {
+			return nil, l.syntaxError(err, valueTypes...)
 		}
 
 		fields = append(fields, ObjectField{key.Value().(string), value})
 	}
 
-	return Object{fields}, nil
+	return newObject(fields), nil
 }
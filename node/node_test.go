@@ -1,8 +1,12 @@
 package node
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/a-skua/json-parser/token"
 )
 
 func TestLex(t *testing.T) {
@@ -56,11 +60,23 @@ func TestLex(t *testing.T) {
 		},
 		"array (err)": {
 			input:   "[1,2,3,]",
-			wantErr: "Unexpected End of Array",
+			wantErr: "1:8: Unexpected token: ']', expected String, Number, True, False, Null, LeftBrace or LeftBracket",
 		},
 		"array (err2)": {
 			input:   "[1,2,3,,]",
-			wantErr: "Unexpected Comma",
+			wantErr: "1:8: Unexpected token: ',', expected String, Number, True, False, Null, LeftBrace or LeftBracket",
+		},
+		"array (err3)": {
+			input:   "[1 2]",
+			wantErr: "1:4: Unexpected token: '2', expected Comma or RightBracket",
+		},
+		"array (err4)": {
+			input:   "[,1]",
+			wantErr: "1:2: Unexpected token: ',', expected String, Number, True, False, Null, LeftBrace or LeftBracket",
+		},
+		"array (err5)": {
+			input:   "[1,",
+			wantErr: "1:4: Unexpected end of input, expected String, Number, True, False, Null, LeftBrace or LeftBracket",
 		},
 		"object": {
 			input: `{"key1": "value1", "key2": 123, "key3": true, "key4": false, "key5": null}
@@ -68,6 +84,42 @@ func TestLex(t *testing.T) {
 {}`,
 			want: `[{"key1":"value1","key2":123,"key3":true,"key4":false,"key5":null} {"array":["hello",123,true,false,null]} {}]`,
 		},
+		"object (err)": {
+			input:   `{"key": 1,}`,
+			wantErr: "1:11: Unexpected token: '}', expected String",
+		},
+		"object (err2)": {
+			input:   `{[1]: 1}`,
+			wantErr: "1:2: Unexpected token: '[', expected String or RightBrace",
+		},
+		"object (err3)": {
+			input:   `{"key" 1}`,
+			wantErr: "1:8: Unexpected token: '1', expected Colon",
+		},
+		"object (err4)": {
+			input:   `{"key": }`,
+			wantErr: "1:9: Unexpected token: '}', expected String, Number, True, False, Null, LeftBrace or LeftBracket",
+		},
+		"object (err5)": {
+			input:   `{"key": 1 "key2": 2}`,
+			wantErr: "1:11: Unexpected token: '\"key2\"', expected Comma or RightBrace",
+		},
+		"object (err6)": {
+			input:   `{"key": 1`,
+			wantErr: "1:10: Unexpected end of input, expected Comma or RightBrace",
+		},
+		"string (escape)": {
+			input: `["a\"b", "\u000a\/", {"k\"ey": "\t"}]`,
+			want:  `[["a\"b","\n/",{"k\"ey":"\t"}]]`,
+		},
+		"string (err)": {
+			input:   `["\ud83dA"]`,
+			wantErr: "1:9: Invalid string: '\"\\ud83dA' (Expected low surrogate: 'A')",
+		},
+		"number (err)": {
+			input:   "[1, 0.]",
+			wantErr: "1:7: Invalid number: '0.]' (Expected digit after '.': ']')",
+		},
 	}
 
 	for name, tt := range tests {
@@ -81,6 +133,333 @@ func TestLex(t *testing.T) {
 			if err == nil && tt.want != got {
 				t.Fatalf("Lex(%s) = %v, want %v", tt.input, got, tt.want)
 			}
+
+			nodes, err = LexBytes([]byte(tt.input))
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("LexBytes(%s) error: %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+
+			got = fmt.Sprint(nodes)
+			if err == nil && tt.want != got {
+				t.Fatalf("LexBytes(%s) = %v, want %v", tt.input, got, tt.want)
+			}
+
+			nodes, err = LexReader(strings.NewReader(tt.input))
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("LexReader(%s) error: %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+
+			got = fmt.Sprint(nodes)
+			if err == nil && tt.want != got {
+				t.Fatalf("LexReader(%s) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestString_Value(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    string
+		wantRaw string
+	}{
+		"plain": {
+			input:   `"Hello, 世界!"`,
+			want:    "Hello, 世界!",
+			wantRaw: `"Hello, 世界!"`,
+		},
+		"empty": {
+			input:   `""`,
+			want:    "",
+			wantRaw: `""`,
+		},
+		"escapes": {
+			input:   `"\"\\\/\b\f\n\r\t"`,
+			want:    "\"\\/\b\f\n\r\t",
+			wantRaw: `"\"\\\/\b\f\n\r\t"`,
+		},
+		"unicode": {
+			input:   `"a\u3042\u00E9b"`,
+			want:    "aあéb",
+			wantRaw: `"a\u3042\u00E9b"`,
+		},
+		"surrogate pair": {
+			input:   `"\ud83d\ude00"`,
+			want:    "😀",
+			wantRaw: `"\ud83d\ude00"`,
+		},
+		"lone high surrogate": {
+			input:   `"\ud83dA"`,
+			want:    "�A",
+			wantRaw: `"\ud83dA"`,
+		},
+		"lone low surrogate": {
+			input:   `"\ude00\ude00"`,
+			want:    "��",
+			wantRaw: `"\ude00\ude00"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodes, err := Lex(tt.input, Lenient())
+			if err != nil {
+				t.Fatalf("Lex(%s) error: %v", tt.input, err)
+			}
+
+			s := nodes[0].(String)
+			if got := s.Value(); got != tt.want {
+				t.Fatalf("String.Value() = %q, want %q", got, tt.want)
+			}
+			if got := s.Raw(); got != tt.wantRaw {
+				t.Fatalf("String.Raw() = %q, want %q", got, tt.wantRaw)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		"value": {
+			input: `{"key": [1, "two", true, false, null]}`,
+			want:  `{"key":[1,"two",true,false,null]}`,
+		},
+		"surrounding whitespace": {
+			input: " \n\t123\r\n ",
+			want:  "123",
+		},
+		"number out of range": {
+			input: `[1e400, -1e400]`,
+			want:  `[1e400,-1e400]`,
+		},
+		"empty": {
+			input:   "",
+			wantErr: "1:1: Unexpected end of input, expected String, Number, True, False, Null, LeftBrace or LeftBracket",
+		},
+		"whitespace only": {
+			input:   "  \n",
+			wantErr: "2:1: Unexpected end of input, expected String, Number, True, False, Null, LeftBrace or LeftBracket",
+		},
+		"multiple values": {
+			input:   "1 2 {}",
+			wantErr: "1:3: Unexpected token: '2'",
+		},
+		"trailing array": {
+			input:   "{}\n[1,",
+			wantErr: "2:1: Unexpected token: '['",
+		},
+		"trailing comma": {
+			input:   "[1],",
+			wantErr: "1:4: Unexpected token: ','",
+		},
+		"trailing garbage": {
+			input:   "true x",
+			wantErr: "1:6: Unexpected character: 'x'",
+		},
+		"stray comma": {
+			input:   ",",
+			wantErr: "1:1: Unexpected token: ',', expected String, Number, True, False, Null, LeftBrace or LeftBracket",
+		},
+		"stray right bracket": {
+			input:   "]",
+			wantErr: "1:1: Unexpected token: ']', expected String, Number, True, False, Null, LeftBrace or LeftBracket",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			parsers := map[string]func(string) (Node, error){
+				"Parse": func(input string) (Node, error) {
+					return Parse(input)
+				},
+				"ParseReader": func(input string) (Node, error) {
+					return ParseReader(strings.NewReader(input))
+				},
+				"ParseBytes": func(input string) (Node, error) {
+					return ParseBytes([]byte(input))
+				},
+			}
+			for fn, parse := range parsers {
+				node, err := parse(tt.input)
+				if err != nil && err.Error() != tt.wantErr {
+					t.Fatalf("%s(%s) error: %v, wantErr %v", fn, tt.input, err, tt.wantErr)
+				}
+				if err == nil && tt.wantErr != "" {
+					t.Fatalf("%s(%s) error: nil, wantErr %v", fn, tt.input, tt.wantErr)
+				}
+				if err == nil && node.String() != tt.want {
+					t.Fatalf("%s(%s) = %v, want %v", fn, tt.input, node, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_invalidUTF8(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		opts    []Option
+		wantErr string
+		wantPos token.Position
+	}{
+		"in string": {
+			input:   "[\"a\xffb\", x]",
+			wantErr: "1:4: Invalid UTF-8: '\"a\xff'",
+			wantPos: token.Position{Offset: 3, RuneOffset: 3, Line: 1, Column: 4},
+		},
+		"after multibyte character": {
+			input:   "[\"世\", \xff]",
+			wantErr: "1:7: Invalid UTF-8: '\xff'",
+			wantPos: token.Position{Offset: 8, RuneOffset: 6, Line: 1, Column: 7},
+		},
+		"encoded surrogate with lenient": {
+			input:   "\"\xed\xa0\x80\"",
+			opts:    []Option{Lenient()},
+			wantErr: "1:2: Invalid UTF-8: '\"\xed'",
+			wantPos: token.Position{Offset: 1, RuneOffset: 1, Line: 1, Column: 2},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			parsers := map[string]func(string, ...Option) (Node, error){
+				"Parse": Parse,
+				"ParseReader": func(input string, opts ...Option) (Node, error) {
+					return ParseReader(strings.NewReader(input), opts...)
+				},
+				"ParseBytes": func(input string, opts ...Option) (Node, error) {
+					return ParseBytes([]byte(input), opts...)
+				},
+			}
+			for fn, parse := range parsers {
+				_, err := parse(tt.input, tt.opts...)
+				var syntaxErr *token.SyntaxError
+				if !errors.As(err, &syntaxErr) || err.Error() != tt.wantErr {
+					t.Fatalf("%s(%q) error: %v, wantErr %v", fn, tt.input, err, tt.wantErr)
+				}
+				if syntaxErr.Kind != token.InvalidUTF8 || syntaxErr.Pos != tt.wantPos {
+					t.Fatalf("%s(%q) error: %s at %+v, want %s at %+v", fn, tt.input, syntaxErr.Kind, syntaxErr.Pos, token.InvalidUTF8, tt.wantPos)
+				}
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	number := func(literal string) Node {
+		n, err := NewNumber(literal)
+		if err != nil {
+			t.Fatalf("NewNumber(%s) error: %v", literal, err)
+		}
+		return n
+	}
+	float := func(f float64) Node {
+		n, err := NewNumberFromFloat(f)
+		if err != nil {
+			t.Fatalf("NewNumberFromFloat(%v) error: %v", f, err)
+		}
+		return n
+	}
+
+	tests := map[string]struct {
+		node      Node
+		want      string
+		wantValue interface{}
+	}{
+		"string": {
+			node:      NewString("a\nb"),
+			want:      `"a\nb"`,
+			wantValue: "a\nb",
+		},
+		"number": {
+			node:      number("1.0e2"),
+			want:      "1.0e2",
+			wantValue: 100.0,
+		},
+		"number from int": {
+			node:      NewNumberFromInt(-9007199254740993),
+			want:      "-9007199254740993",
+			wantValue: int64(-9007199254740993),
+		},
+		"number from uint": {
+			node:      NewNumberFromUint(18446744073709551615),
+			want:      "18446744073709551615",
+			wantValue: uint64(18446744073709551615),
+		},
+		"number from float": {
+			node:      float(1e21),
+			want:      "1e+21",
+			wantValue: 1e21,
+		},
+		"boolean": {
+			node:      NewBoolean(true),
+			want:      "true",
+			wantValue: true,
+		},
+		"null": {
+			node: NewNull(),
+			want: "null",
+		},
+		"array": {
+			node: NewArray(NewString("a"), nil, NewArray()),
+			want: `["a",null,[]]`,
+		},
+		"object": {
+			node: NewObject(ObjectField{"a", NewNumberFromInt(1)}, ObjectField{"b\"", nil}),
+			want: `{"a":1,"b\"":null}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.node.String(); got != tt.want {
+				t.Fatalf("String() = %s, want %s", got, tt.want)
+			}
+			if tt.wantValue == nil {
+				return
+			}
+			if got := tt.node.Value(); got != tt.wantValue {
+				t.Fatalf("Value() = %#v, want %#v", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestNewNumber(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"ok": {
+			input: "-0.5e-3",
+		},
+		"leading zero": {
+			input:   "01",
+			wantErr: "Invalid number: '01'",
+		},
+		"whitespace": {
+			input:   " 1",
+			wantErr: "Invalid number: ' 1'",
+		},
+		"not a number": {
+			input:   "1x",
+			wantErr: "1:2: Unexpected character: 'x'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewNumber(tt.input)
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("NewNumber(%s) error: %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("NewNumber(%s) error: nil, wantErr %v", tt.input, tt.wantErr)
+			}
 		})
 	}
 }
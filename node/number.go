@@ -0,0 +1,204 @@
+package node
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/a-skua/json-parser/token"
+)
+
+// Representation is the Go type of a Number value.
+type Representation uint8
+
+const (
+	_ Representation = iota
+	RepresentationFloat64
+	RepresentationInt64
+	RepresentationUint64
+	RepresentationBigInt
+	RepresentationBigFloat
+	RepresentationRat
+	// RepresentationLiteral keeps the number as written in the source.
+	RepresentationLiteral
+)
+
+// maxExponent bounds the decimal exponent of numbers converted to big.Int and big.Rat,
+// whose size grows with the exponent.
+const maxExponent = 10000
+
+// NewNumber returns the Number written as literal.
+func NewNumber(literal string) (Number, error) {
+	return parseNumber(literal, 0)
+}
+
+func parseNumber(literal string, r Representation) (Number, error) {
+	tokens, err := token.Tokenize([]rune(literal))
+	if err != nil {
+		return Number{}, err
+	}
+	if len(tokens) != 1 || tokens[0].Type != token.Number {
+		return Number{}, fmt.Errorf("Invalid number: '%s'", literal)
+	}
+	return newNumber(tokens[0], r)
+}
+
+func NewNumberFromInt(i int64) Number {
+	return Number{strconv.FormatInt(i, 10), i}
+}
+
+func NewNumberFromUint(u uint64) Number {
+	return Number{strconv.FormatUint(u, 10), u}
+}
+
+// NewNumberFromFloat returns the shortest literal of f.
+// NaN and infinities are not JSON numbers and are reported as errors.
+func NewNumberFromFloat(f float64) (Number, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Number{}, fmt.Errorf("Invalid number: %v", f)
+	}
+	return Number{strconv.FormatFloat(f, 'g', -1, 64), f}, nil
+}
+
+func NewNumberFromBigInt(b *big.Int) Number {
+	return Number{b.String(), new(big.Int).Set(b)}
+}
+
+func (n Number) as(r Representation) (interface{}, error) {
+	switch r {
+	case RepresentationInt64:
+		return n.Int64()
+	case RepresentationUint64:
+		return n.Uint64()
+	case RepresentationBigInt:
+		return n.BigInt()
+	case RepresentationBigFloat:
+		return n.BigFloat()
+	case RepresentationRat:
+		return n.Rat()
+	case RepresentationLiteral:
+		return n.Literal(), nil
+	default:
+		return n.Float64()
+	}
+}
+
+// Literal returns the number as written in the source. The zero Number is 0.
+func (n Number) Literal() string {
+	if n.literal == "" {
+		return "0"
+	}
+	return n.literal
+}
+
+func (n Number) Float64() (float64, error) {
+	f, err := strconv.ParseFloat(n.Literal(), 64)
+	if err != nil {
+		return f, n.error("float64", err)
+	}
+	return f, nil
+}
+
+func (n Number) Int64() (int64, error) {
+	if i, err := strconv.ParseInt(n.Literal(), 10, 64); err == nil {
+		return i, nil
+	}
+	b, err := n.bigInt()
+	if err != nil {
+		return 0, n.error("int64", err)
+	}
+	if !b.IsInt64() {
+		return 0, n.error("int64", strconv.ErrRange)
+	}
+	return b.Int64(), nil
+}
+
+func (n Number) Uint64() (uint64, error) {
+	if i, err := strconv.ParseUint(n.Literal(), 10, 64); err == nil {
+		return i, nil
+	}
+	b, err := n.bigInt()
+	if err != nil {
+		return 0, n.error("uint64", err)
+	}
+	if !b.IsUint64() {
+		return 0, n.error("uint64", strconv.ErrRange)
+	}
+	return b.Uint64(), nil
+}
+
+func (n Number) BigInt() (*big.Int, error) {
+	b, err := n.bigInt()
+	if err != nil {
+		return nil, n.error("big.Int", err)
+	}
+	return b, nil
+}
+
+func (n Number) bigInt() (*big.Int, error) {
+	if b, ok := new(big.Int).SetString(n.Literal(), 10); ok {
+		return b, nil
+	}
+	r, err := n.rat()
+	if err != nil {
+		return nil, err
+	}
+	if !r.IsInt() {
+		return nil, ErrNotInteger
+	}
+	return r.Num(), nil
+}
+
+// BigFloat returns the number with enough precision for its decimal digits.
+func (n Number) BigFloat() (*big.Float, error) {
+	prec := uint(len(n.Literal())*4 + 64)
+	f, _, err := big.ParseFloat(n.Literal(), 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, n.error("big.Float", err)
+	}
+	return f, nil
+}
+
+func (n Number) Rat() (*big.Rat, error) {
+	r, err := n.rat()
+	if err != nil {
+		return nil, n.error("big.Rat", err)
+	}
+	return r, nil
+}
+
+func (n Number) rat() (*big.Rat, error) {
+	if exp := n.exponent(); exp > maxExponent || exp < -maxExponent {
+		return nil, strconv.ErrRange
+	}
+	r, ok := new(big.Rat).SetString(n.Literal())
+	if !ok {
+		return nil, strconv.ErrSyntax
+	}
+	return r, nil
+}
+
+// exponent returns the decimal exponent of the literal, saturating on overflow.
+func (n Number) exponent() int64 {
+	i := strings.IndexAny(n.Literal(), "eE")
+	if i < 0 {
+		return 0
+	}
+	exp, err := strconv.ParseInt(n.Literal()[i+1:], 10, 64)
+	if err != nil && strings.HasPrefix(n.Literal()[i+1:], "-") {
+		return -maxExponent - 1
+	}
+	if err != nil {
+		return maxExponent + 1
+	}
+	return exp
+}
+
+func (n Number) error(typ string, err error) error {
+	if ne, ok := err.(*strconv.NumError); ok {
+		err = ne.Err
+	}
+	return fmt.Errorf("Cannot convert %s to %s: %w", n.Literal(), typ, err)
+}
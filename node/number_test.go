@@ -0,0 +1,214 @@
+package node
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNumber(t *testing.T) {
+	tests := map[string]struct {
+		input       string
+		wantString  string
+		wantFloat64 string
+		wantInt64   string
+		wantUint64  string
+		wantBigInt  string
+		wantRat     string
+	}{
+		"zero value": {
+			wantString:  "0",
+			wantFloat64: "0",
+			wantInt64:   "0",
+			wantUint64:  "0",
+			wantBigInt:  "0",
+			wantRat:     "0/1",
+		},
+		"integer": {
+			input:       "123",
+			wantString:  "123",
+			wantFloat64: "123",
+			wantInt64:   "123",
+			wantUint64:  "123",
+			wantBigInt:  "123",
+			wantRat:     "123/1",
+		},
+		"fraction keeps literal": {
+			input:       "1.0",
+			wantString:  "1.0",
+			wantFloat64: "1",
+			wantInt64:   "1",
+			wantUint64:  "1",
+			wantBigInt:  "1",
+			wantRat:     "1/1",
+		},
+		"exponent": {
+			input:       "-1.5e2",
+			wantString:  "-1.5e2",
+			wantFloat64: "-150",
+			wantInt64:   "-150",
+			wantUint64:  "Cannot convert -1.5e2 to uint64: value out of range",
+			wantBigInt:  "-150",
+			wantRat:     "-150/1",
+		},
+		"not an integer": {
+			input:       "0.5",
+			wantString:  "0.5",
+			wantFloat64: "0.5",
+			wantInt64:   "Cannot convert 0.5 to int64: Not an integer",
+			wantUint64:  "Cannot convert 0.5 to uint64: Not an integer",
+			wantBigInt:  "Cannot convert 0.5 to big.Int: Not an integer",
+			wantRat:     "1/2",
+		},
+		"beyond float64 precision": {
+			input:       "9007199254740993",
+			wantString:  "9007199254740993",
+			wantFloat64: "9.007199254740992e+15",
+			wantInt64:   "9007199254740993",
+			wantUint64:  "9007199254740993",
+			wantBigInt:  "9007199254740993",
+			wantRat:     "9007199254740993/1",
+		},
+		"beyond int64": {
+			input:       "18446744073709551615",
+			wantString:  "18446744073709551615",
+			wantFloat64: "1.8446744073709552e+19",
+			wantInt64:   "Cannot convert 18446744073709551615 to int64: value out of range",
+			wantUint64:  "18446744073709551615",
+			wantBigInt:  "18446744073709551615",
+			wantRat:     "18446744073709551615/1",
+		},
+		"beyond float64": {
+			input:       "1e400",
+			wantString:  "1e400",
+			wantFloat64: "Cannot convert 1e400 to float64: value out of range",
+			wantInt64:   "Cannot convert 1e400 to int64: value out of range",
+			wantUint64:  "Cannot convert 1e400 to uint64: value out of range",
+			wantBigInt:  "1" + fmt.Sprintf("%0400d", 0),
+			wantRat:     "1" + fmt.Sprintf("%0400d", 0) + "/1",
+		},
+		"huge exponent": {
+			input:       "1e100000",
+			wantString:  "1e100000",
+			wantFloat64: "Cannot convert 1e100000 to float64: value out of range",
+			wantInt64:   "Cannot convert 1e100000 to int64: value out of range",
+			wantUint64:  "Cannot convert 1e100000 to uint64: value out of range",
+			wantBigInt:  "Cannot convert 1e100000 to big.Int: value out of range",
+			wantRat:     "Cannot convert 1e100000 to big.Rat: value out of range",
+		},
+	}
+
+	sprint := func(v interface{}, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprint(v)
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var n Number
+			if tt.input != "" {
+				nodes, err := Lex(tt.input, NumberAs(RepresentationLiteral))
+				if err != nil {
+					t.Fatalf("Lex(%s) error: %v", tt.input, err)
+				}
+				n = nodes[0].(Number)
+			}
+
+			if got := n.String(); got != tt.wantString {
+				t.Errorf("Number.String() = %s, want %s", got, tt.wantString)
+			}
+			if got := sprint(n.Float64()); got != tt.wantFloat64 {
+				t.Errorf("Number.Float64() = %s, want %s", got, tt.wantFloat64)
+			}
+			if got := sprint(n.Int64()); got != tt.wantInt64 {
+				t.Errorf("Number.Int64() = %s, want %s", got, tt.wantInt64)
+			}
+			if got := sprint(n.Uint64()); got != tt.wantUint64 {
+				t.Errorf("Number.Uint64() = %s, want %s", got, tt.wantUint64)
+			}
+			if got := sprint(n.BigInt()); got != tt.wantBigInt {
+				t.Errorf("Number.BigInt() = %s, want %s", got, tt.wantBigInt)
+			}
+			if got := sprint(n.Rat()); got != tt.wantRat {
+				t.Errorf("Number.Rat() = %s, want %s", got, tt.wantRat)
+			}
+		})
+	}
+}
+
+func TestNumberAs(t *testing.T) {
+	tests := map[string]struct {
+		input          string
+		representation Representation
+		want           string
+		wantErr        string
+	}{
+		"float64": {
+			input:          "9007199254740993",
+			representation: RepresentationFloat64,
+			want:           "float64 9.007199254740992e+15",
+		},
+		"float64 (err)": {
+			input:          "[1e400]",
+			representation: RepresentationFloat64,
+			wantErr:        "Cannot convert 1e400 to float64: value out of range",
+		},
+		"default out of range": {
+			input: "1e400",
+			want:  "float64 +Inf",
+		},
+		"int64": {
+			input:          "9007199254740993",
+			representation: RepresentationInt64,
+			want:           "int64 9007199254740993",
+		},
+		"int64 (err)": {
+			input:          "[1.5]",
+			representation: RepresentationInt64,
+			wantErr:        "Cannot convert 1.5 to int64: Not an integer",
+		},
+		"uint64": {
+			input:          "18446744073709551615",
+			representation: RepresentationUint64,
+			want:           "uint64 18446744073709551615",
+		},
+		"big.Int": {
+			input:          "1e20",
+			representation: RepresentationBigInt,
+			want:           "*big.Int 100000000000000000000",
+		},
+		"big.Float": {
+			input:          "0.1",
+			representation: RepresentationBigFloat,
+			want:           "*big.Float 0.1",
+		},
+		"big.Rat": {
+			input:          "0.1",
+			representation: RepresentationRat,
+			want:           "*big.Rat 1/10",
+		},
+		"literal": {
+			input:          "1.0e0",
+			representation: RepresentationLiteral,
+			want:           "string 1.0e0",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodes, err := Lex(tt.input, NumberAs(tt.representation))
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Lex(%s) error: %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			v := nodes[0].Value()
+			if got := fmt.Sprintf("%T %v", v, v); got != tt.want {
+				t.Fatalf("Number.Value() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package node
+
+import (
+	"github.com/a-skua/json-parser/token"
+)
+
+type Option func(*options)
+
+type options struct {
+	tokenizer []token.Option
+	number    Representation
+}
+
+// Lenient accepts unescaped control characters and unpaired surrogates in strings.
+// Unpaired surrogates are decoded as U+FFFD.
+func Lenient() Option {
+	return func(o *options) {
+		o.tokenizer = append(o.tokenizer, token.Lenient())
+	}
+}
+
+// NumberAs selects the Go type returned by Number.Value.
+// Numbers that do not fit the representation are reported as errors.
+// By default, Value is a float64, ±Inf for numbers out of its range, and
+// only Number.Float64 reports the error.
+func NumberAs(r Representation) Option {
+	return func(o *options) {
+		o.number = r
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
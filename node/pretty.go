@@ -0,0 +1,291 @@
+package node
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+type PrettyOption func(*prettyOptions)
+
+type prettyOptions struct {
+	indent       string
+	prefix       string
+	sortKeys     bool
+	compact      bool
+	width        int
+	finalNewline bool
+	escape       Escape
+}
+
+// Indent sets the string repeated once per nesting level. The default is two
+// spaces.
+func Indent(indent string) PrettyOption {
+	return func(o *prettyOptions) {
+		o.indent = indent
+	}
+}
+
+// Prefix sets the string that starts every line.
+func Prefix(prefix string) PrettyOption {
+	return func(o *prettyOptions) {
+		o.prefix = prefix
+	}
+}
+
+// SortKeys writes object members in key order instead of source order.
+func SortKeys() PrettyOption {
+	return func(o *prettyOptions) {
+		o.sortKeys = true
+	}
+}
+
+// CompactScalarArrays writes arrays without objects or arrays on one line.
+// With MaxWidth, such arrays are wrapped to fill lines instead.
+func CompactScalarArrays() PrettyOption {
+	return func(o *prettyOptions) {
+		o.compact = true
+	}
+}
+
+// MaxWidth writes objects and arrays on one line when the line fits in width
+// columns. Values are never split, so longer lines remain possible.
+func MaxWidth(width int) PrettyOption {
+	return func(o *prettyOptions) {
+		o.width = width
+	}
+}
+
+// FinalNewline ends the output with a newline.
+func FinalNewline() PrettyOption {
+	return func(o *prettyOptions) {
+		o.finalNewline = true
+	}
+}
+
+// Escaping quotes keys and strings with escape, as Quote does.
+func Escaping(escape Escape) PrettyOption {
+	return func(o *prettyOptions) {
+		o.escape = escape
+	}
+}
+
+func newPrettyOptions(opts []PrettyOption) prettyOptions {
+	o := prettyOptions{indent: "  "}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Pretty returns n indented over multiple lines.
+func Pretty(n Node, opts ...PrettyOption) string {
+	var b strings.Builder
+	WritePretty(&b, n, opts...)
+	return b.String()
+}
+
+// WritePretty writes n indented over multiple lines to w.
+func WritePretty(w io.Writer, n Node, opts ...PrettyOption) error {
+	o := newPrettyOptions(opts)
+	p := printer{encoder: newEncoder(w, o.escape), opts: o}
+	p.writeString(p.opts.prefix)
+	p.value(n, 0, runeCount(p.opts.prefix), 0)
+	if p.opts.finalNewline {
+		p.writeByte('\n')
+	}
+	return p.flush()
+}
+
+type printer struct {
+	*encoder
+	opts prettyOptions
+}
+
+// value writes n starting at column, followed on the same line by trailing
+// columns of punctuation.
+func (p *printer) value(n Node, depth, column, trailing int) {
+	switch n := n.(type) {
+	case Object:
+		if len(n.fields) == 0 || p.fits(n, column+trailing) {
+			p.inline(n)
+			return
+		}
+
+		fields := p.fields(n)
+		p.writeByte('{')
+		for i, field := range fields {
+			if i > 0 {
+				p.writeByte(',')
+			}
+			p.newline(depth + 1)
+			key := Quote(field.Key, p.escape) + ": "
+			p.writeString(key)
+			p.value(field.Value, depth+1, p.column(depth+1)+runeCount(key), p.comma(i, len(fields)))
+		}
+		p.newline(depth)
+		p.writeByte('}')
+
+	case Array:
+		if len(n.nodes) == 0 || p.fits(n, column+trailing) {
+			p.inline(n)
+			return
+		}
+		if p.opts.compact && scalars(n) {
+			if p.opts.width <= 0 {
+				p.inline(n)
+			} else {
+				p.fill(n, depth)
+			}
+			return
+		}
+
+		p.writeByte('[')
+		for i, node := range n.nodes {
+			if i > 0 {
+				p.writeByte(',')
+			}
+			p.newline(depth + 1)
+			p.value(node, depth+1, p.column(depth+1), p.comma(i, len(n.nodes)))
+		}
+		p.newline(depth)
+		p.writeByte(']')
+
+	default:
+		p.writeScalar(n)
+	}
+}
+
+// fill writes the scalars of a on as few lines as fit the width.
+func (p *printer) fill(a Array, depth int) {
+	p.writeByte('[')
+	column := p.opts.width
+	for i, node := range a.nodes {
+		s := p.scalar(node)
+		width := runeCount(s) + p.comma(i, len(a.nodes))
+		if i > 0 && column+1+width <= p.opts.width {
+			p.writeByte(' ')
+			column++
+		} else {
+			p.newline(depth + 1)
+			column = p.column(depth + 1)
+		}
+		p.writeString(s)
+		if i < len(a.nodes)-1 {
+			p.writeByte(',')
+		}
+		column += width
+	}
+	p.newline(depth)
+	p.writeByte(']')
+}
+
+func (p *printer) newline(depth int) {
+	p.writeByte('\n')
+	p.writeString(p.opts.prefix)
+	for i := 0; i < depth; i++ {
+		p.writeString(p.opts.indent)
+	}
+}
+
+func (p *printer) column(depth int) int {
+	return runeCount(p.opts.prefix) + depth*runeCount(p.opts.indent)
+}
+
+func (p *printer) comma(i, n int) int {
+	if i < n-1 {
+		return 1
+	}
+	return 0
+}
+
+func (p *printer) fields(o Object) []ObjectField {
+	if !p.opts.sortKeys {
+		return o.fields
+	}
+	fields := slices.Clone(o.fields)
+	slices.SortStableFunc(fields, func(a, b ObjectField) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return fields
+}
+
+// fits reports whether n written inline ends within the width.
+func (p *printer) fits(n Node, column int) bool {
+	return p.opts.width > 0 && column+p.inlineWidth(n, p.opts.width-column) <= p.opts.width
+}
+
+// inlineWidth returns the width of n written inline, or more than limit once
+// it exceeds limit.
+func (p *printer) inlineWidth(n Node, limit int) int {
+	if limit < 0 {
+		return 1
+	}
+
+	switch n := n.(type) {
+	case Object:
+		width := 2 + 2*max(len(n.fields)-1, 0)
+		for _, field := range n.fields {
+			if width > limit {
+				break
+			}
+			width += runeCount(Quote(field.Key, p.escape)) + 2
+			width += p.inlineWidth(field.Value, limit-width)
+		}
+		return width
+	case Array:
+		width := 2 + 2*max(len(n.nodes)-1, 0)
+		for _, node := range n.nodes {
+			if width > limit {
+				break
+			}
+			width += p.inlineWidth(node, limit-width)
+		}
+		return width
+	default:
+		return runeCount(p.scalar(n))
+	}
+}
+
+// inline writes n on one line with a space after each ':' and ','.
+func (p *printer) inline(n Node) {
+	switch n := n.(type) {
+	case Object:
+		p.writeByte('{')
+		for i, field := range p.fields(n) {
+			if i > 0 {
+				p.writeString(", ")
+			}
+			p.writeQuote(field.Key)
+			p.writeString(": ")
+			p.inline(field.Value)
+		}
+		p.writeByte('}')
+	case Array:
+		p.writeByte('[')
+		for i, node := range n.nodes {
+			if i > 0 {
+				p.writeString(", ")
+			}
+			p.inline(node)
+		}
+		p.writeByte(']')
+	default:
+		p.writeScalar(n)
+	}
+}
+
+func scalars(a Array) bool {
+	for _, node := range a.nodes {
+		switch node.(type) {
+		case Object, Array:
+			return false
+		}
+	}
+	return true
+}
+
+func runeCount(s string) int {
+	return utf8.RuneCountInString(s)
+}
@@ -0,0 +1,150 @@
+package node
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPretty(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		opts  []PrettyOption
+		want  string
+	}{
+		"scalar": {
+			input: `"a\u0001"`,
+			want:  `"a\u0001"`,
+		},
+		"empty": {
+			input: `{"a": {}, "b": []}`,
+			want: `{
+  "a": {},
+  "b": []
+}`,
+		},
+		"nested": {
+			input: `{"a": [1, {"b": null}], "c": "d"}`,
+			want: `{
+  "a": [
+    1,
+    {
+      "b": null
+    }
+  ],
+  "c": "d"
+}`,
+		},
+		"indent and prefix": {
+			input: `{"a": [1]}`,
+			opts:  []PrettyOption{Indent("\t"), Prefix("// ")},
+			want:  "// {\n// \t\"a\": [\n// \t\t1\n// \t]\n// }",
+		},
+		"sort keys": {
+			input: `{"b": 1, "a": {"d": 2, "c": 3}, "B": 4}`,
+			opts:  []PrettyOption{SortKeys(), MaxWidth(30)},
+			want: `{
+  "B": 4,
+  "a": {"c": 3, "d": 2},
+  "b": 1
+}`,
+		},
+		"compact scalar arrays": {
+			input: `{"a": [1, "b", true, null], "c": [[1], {"d": [2, 3]}]}`,
+			opts:  []PrettyOption{CompactScalarArrays()},
+			want: `{
+  "a": [1, "b", true, null],
+  "c": [
+    [1],
+    {
+      "d": [2, 3]
+    }
+  ]
+}`,
+		},
+		"max width": {
+			input: `{"short": {"a": [1, 2]}, "long": {"abcdefghij": [1, 2, 3, 4, 5]}}`,
+			opts:  []PrettyOption{MaxWidth(33)},
+			want: `{
+  "short": {"a": [1, 2]},
+  "long": {
+    "abcdefghij": [1, 2, 3, 4, 5]
+  }
+}`,
+		},
+		"max width counts trailing comma": {
+			input: `[[1, 2, 3], [4, 5, 6]]`,
+			opts:  []PrettyOption{MaxWidth(11)},
+			want: `[
+  [
+    1,
+    2,
+    3
+  ],
+  [4, 5, 6]
+]`,
+		},
+		"max width counts runes": {
+			input: `["ああああ"]`,
+			opts:  []PrettyOption{MaxWidth(8)},
+			want:  `["ああああ"]`,
+		},
+		"fill scalar arrays": {
+			input: `{"primes": [2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37]}`,
+			opts:  []PrettyOption{CompactScalarArrays(), MaxWidth(20)},
+			want: `{
+  "primes": [
+    2, 3, 5, 7, 11,
+    13, 17, 19, 23,
+    29, 31, 37
+  ]
+}`,
+		},
+		"escaping": {
+			input: `{"<": ["ああ", "&"]}`,
+			opts:  []PrettyOption{Escaping(EscapeASCII | EscapeHTML), MaxWidth(30)},
+			want: `{
+  "\u003c": [
+    "\u3042\u3042",
+    "\u0026"
+  ]
+}`,
+		},
+		"final newline": {
+			input: `[]`,
+			opts:  []PrettyOption{FinalNewline()},
+			want:  "[]\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%s) error: %v", tt.input, err)
+			}
+
+			got := Pretty(n, tt.opts...)
+			if got != tt.want {
+				t.Fatalf("Pretty(%s) =\n%s\n(want:\n%s\n)", tt.input, got, tt.want)
+			}
+
+			if _, err := Parse(got); len(tt.opts) == 0 && err != nil {
+				t.Fatalf("Parse(Pretty(%s)) error: %v", tt.input, err)
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("Write failed")
+}
+
+func TestWritePretty(t *testing.T) {
+	n := NewArray(NewString(strings.Repeat("a", 8192)))
+	if err := WritePretty(failingWriter{}, n); err == nil || err.Error() != "Write failed" {
+		t.Fatalf("WritePretty() error: %v (want: Write failed)", err)
+	}
+}
@@ -0,0 +1,92 @@
+package node
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Escape selects characters Quote escapes in addition to those JSON requires.
+type Escape uint8
+
+const (
+	// EscapeASCII escapes all non-ASCII characters as \uXXXX.
+	EscapeASCII Escape = 1 << iota
+	// EscapeHTML escapes '<', '>' and '&'.
+	EscapeHTML
+	// EscapeLineTerminators escapes U+2028 and U+2029.
+	EscapeLineTerminators
+)
+
+const hex = "0123456789abcdef"
+
+// Quote returns s as a JSON string literal.
+func Quote(s string, escape Escape) string {
+	return string(AppendQuote(make([]byte, 0, len(s)+2), s, escape))
+}
+
+// AppendQuote appends the JSON string literal of s to dst.
+// Invalid UTF-8 is replaced with U+FFFD.
+func AppendQuote(dst []byte, s string, escape Escape) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !needsEscape(r, size, escape) {
+			i += size
+			continue
+		}
+
+		dst = append(dst, s[start:i]...)
+		switch r {
+		case '"', '\\':
+			dst = append(dst, '\\', byte(r))
+		case '\b':
+			dst = append(dst, '\\', 'b')
+		case '\f':
+			dst = append(dst, '\\', 'f')
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		default:
+			if r == utf8.RuneError && size == 1 && escape&EscapeASCII == 0 {
+				dst = utf8.AppendRune(dst, utf8.RuneError)
+				break
+			}
+			if r > 0xFFFF {
+				r1, r2 := utf16.EncodeRune(r)
+				dst = appendHex(dst, r1)
+				dst = appendHex(dst, r2)
+				break
+			}
+			dst = appendHex(dst, r)
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+func needsEscape(r rune, size int, escape Escape) bool {
+	switch {
+	case r < 0x20, r == '"', r == '\\':
+		return true
+	case r == utf8.RuneError && size == 1:
+		return true
+	case escape&EscapeHTML != 0 && (r == '<' || r == '>' || r == '&'):
+		return true
+	case escape&EscapeLineTerminators != 0 && (r == '\u2028' || r == '\u2029'):
+		return true
+	case escape&EscapeASCII != 0 && r >= utf8.RuneSelf:
+		return true
+	default:
+		return false
+	}
+}
+
+func appendHex(dst []byte, r rune) []byte {
+	return append(dst, '\\', 'u', hex[r>>12&0xF], hex[r>>8&0xF], hex[r>>4&0xF], hex[r&0xF])
+}
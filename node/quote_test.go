@@ -0,0 +1,67 @@
+package node
+
+import (
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := map[string]struct {
+		input  string
+		escape Escape
+		want   string
+	}{
+		"plain": {
+			input: "Hello, 世界!",
+			want:  `"Hello, 世界!"`,
+		},
+		"quotation mark and reverse solidus": {
+			input: `a"b\c`,
+			want:  `"a\"b\\c"`,
+		},
+		"control characters": {
+			input: "\b\f\n\r\t\x00\x1f",
+			want:  `"\b\f\n\r\t\u0000\u001f"`,
+		},
+		"solidus": {
+			input: "</script>",
+			want:  `"</script>"`,
+		},
+		"invalid UTF-8": {
+			input: "a\xffb",
+			want:  "\"a\uFFFDb\"",
+		},
+		"ascii": {
+			input:  "é世😀",
+			escape: EscapeASCII,
+			want:   `"\u00e9\u4e16\ud83d\ude00"`,
+		},
+		"ascii: invalid UTF-8": {
+			input:  "a\xffb",
+			escape: EscapeASCII,
+			want:   `"a\ufffdb"`,
+		},
+		"html": {
+			input:  "<a>&",
+			escape: EscapeHTML,
+			want:   `"\u003ca\u003e\u0026"`,
+		},
+		"line terminators": {
+			input:  "a\u2028b\u2029",
+			escape: EscapeLineTerminators,
+			want:   `"a\u2028b\u2029"`,
+		},
+		"line terminators: not escaped by default": {
+			input: "a\u2028b",
+			want:  "\"a\u2028b\"",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Quote(tt.input, tt.escape)
+			if got != tt.want {
+				t.Fatalf("Quote(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
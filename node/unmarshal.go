@@ -0,0 +1,501 @@
+package node
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Unmarshaler is implemented by types that read themselves from a node.
+type Unmarshaler interface {
+	UnmarshalNode(Node) error
+}
+
+// UnmarshalTypeError reports a value that cannot be stored in a Go type.
+// Path is the JSON Pointer of the value.
+type UnmarshalTypeError struct {
+	Path  string
+	Value Type
+	Type  reflect.Type
+	Err   error
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "root"
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("Cannot unmarshal %s into %s at %s: %v", e.Value, e.Type, path, e.Err)
+	}
+	return fmt.Sprintf("Cannot unmarshal %s into %s at %s", e.Value, e.Type, path)
+}
+
+func (e *UnmarshalTypeError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	nodeType            = reflect.TypeFor[Node]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Unmarshal stores n in the value v points to.
+//
+// Objects are stored in structs by the `json` tags of their fields, matching
+// keys exactly or else case-insensitively, and in maps with string or integer
+// keys. Arrays are stored in slices and arrays, strings also in []byte as
+// base64, and numbers also in json.Number. Null sets pointers, maps, slices
+// and interfaces to nil. An empty interface receives map[string]any, []any,
+// string, float64, bool or nil, and Node or a concrete node type receives n
+// itself.
+//
+// Types implementing Unmarshaler, json.Unmarshaler or
+// encoding.TextUnmarshaler, the last for strings only, read the value
+// themselves, tried in that order.
+//
+// Unmarshal stores as much as it can and returns an *UnmarshalTypeError for
+// every mismatch, joined with errors.Join. A nil pointer to a value that
+// cannot be stored is left nil.
+func Unmarshal(n Node, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("Cannot unmarshal into %s: not a non-nil pointer", reflect.TypeOf(v))
+	}
+
+	d := decoder{}
+	d.value(n, rv.Elem())
+	return errors.Join(d.errs...)
+}
+
+// Decode parses input and stores the document in v.
+func Decode(input string, v any, opts ...Option) error {
+	n, err := Parse(input, opts...)
+	if err != nil {
+		return err
+	}
+	return Unmarshal(n, v)
+}
+
+type decoder struct {
+	path []string
+	errs []error
+}
+
+func (d *decoder) mismatch(n Node, t reflect.Type, err error) {
+	d.errs = append(d.errs, &UnmarshalTypeError{pointerString(d.path), n.Type(), t, err})
+}
+
+func (d *decoder) push(token string) {
+	d.path = append(d.path, token)
+}
+
+func (d *decoder) pop() {
+	d.path = d.path[:len(d.path)-1]
+}
+
+func (d *decoder) value(n Node, v reflect.Value) {
+	if v.Type() == nodeType || (v.Kind() != reflect.Interface && reflect.TypeOf(n) == v.Type()) {
+		v.Set(reflect.ValueOf(n))
+		return
+	}
+
+	_, null := n.(Null)
+	if null {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			v.SetZero()
+			return
+		}
+	}
+	if v.Kind() != reflect.Interface && v.Kind() != reflect.Pointer && v.Type().Implements(nodeType) {
+		if !null {
+			d.mismatch(n, v.Type(), nil)
+		}
+		return
+	}
+	if d.hook(n, v) || null {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		d.pointerValue(n, v)
+	case reflect.Interface:
+		d.interfaceValue(n, v)
+	case reflect.Struct:
+		d.structValue(n, v)
+	case reflect.Map:
+		d.mapValue(n, v)
+	case reflect.Slice:
+		d.sliceValue(n, v)
+	case reflect.Array:
+		d.arrayValue(n, v)
+	default:
+		d.scalarValue(n, v)
+	}
+}
+
+// pointerValue stores n in the element of v. A nil v is set to a new
+// element unless n cannot be stored in it at all.
+func (d *decoder) pointerValue(n Node, v reflect.Value) {
+	if !v.IsNil() {
+		d.value(n, v.Elem())
+		return
+	}
+
+	elem := reflect.New(v.Type().Elem())
+	errs := len(d.errs)
+	d.value(n, elem.Elem())
+	path := pointerString(d.path)
+	for _, err := range d.errs[errs:] {
+		if err, ok := err.(*UnmarshalTypeError); ok && err.Path == path {
+			return
+		}
+	}
+	v.Set(elem)
+}
+
+// hook lets the first of Unmarshaler, json.Unmarshaler and
+// encoding.TextUnmarshaler implemented by the address of v read n,
+// and reports whether there was one.
+func (d *decoder) hook(n Node, v reflect.Value) bool {
+	if !v.CanAddr() {
+		return false
+	}
+
+	var err error
+	switch u := v.Addr().Interface().(type) {
+	case Unmarshaler:
+		err = u.UnmarshalNode(n)
+	case json.Unmarshaler:
+		err = u.UnmarshalJSON([]byte(n.String()))
+	case encoding.TextUnmarshaler:
+		switch n := n.(type) {
+		case String:
+			err = u.UnmarshalText([]byte(n.value))
+		case Null:
+		default:
+			d.mismatch(n, v.Type(), nil)
+		}
+	default:
+		return false
+	}
+	if err != nil {
+		d.mismatch(n, v.Type(), err)
+	}
+	return true
+}
+
+func (d *decoder) interfaceValue(n Node, v reflect.Value) {
+	if !v.IsNil() && v.Elem().Kind() == reflect.Pointer && !v.Elem().IsNil() {
+		d.value(n, v.Elem())
+		return
+	}
+	if v.NumMethod() > 0 {
+		d.mismatch(n, v.Type(), nil)
+		return
+	}
+
+	value, err := anyValue(n)
+	if err != nil {
+		d.mismatch(n, v.Type(), err)
+		return
+	}
+	if value == nil {
+		v.SetZero()
+		return
+	}
+	v.Set(reflect.ValueOf(value))
+}
+
+// anyValue returns n as map[string]any, []any, string, float64, bool or nil.
+func anyValue(n Node) (any, error) {
+	switch n := n.(type) {
+	case Object:
+		m := make(map[string]any, len(n.fields))
+		for _, field := range n.fields {
+			value, err := anyValue(field.Value)
+			if err != nil {
+				return nil, err
+			}
+			m[field.Key] = value
+		}
+		return m, nil
+	case Array:
+		s := make([]any, len(n.nodes))
+		for i, node := range n.nodes {
+			value, err := anyValue(node)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = value
+		}
+		return s, nil
+	case Number:
+		return n.Float64()
+	case String, Boolean:
+		return n.Value(), nil
+	default:
+		return nil, nil
+	}
+}
+
+func (d *decoder) structValue(n Node, v reflect.Value) {
+	o, ok := n.(Object)
+	if !ok {
+		d.mismatch(n, v.Type(), nil)
+		return
+	}
+
+	fields := cachedFields(v.Type())
+	for _, member := range o.fields {
+		f, ok := fields.lookup(member.Key)
+		if !ok {
+			continue
+		}
+
+		d.push(member.Key)
+		if fv, ok := fieldByIndex(v, f.index); !ok {
+			d.mismatch(member.Value, v.Type(), fmt.Errorf("Cannot set embedded pointer to unexported struct for %s", f.name))
+		} else if f.quoted {
+			d.quotedValue(member.Value, fv)
+		} else {
+			d.value(member.Value, fv)
+		}
+		d.pop()
+	}
+}
+
+// fieldByIndex returns the field of v at index, allocating nil embedded
+// pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// quotedValue stores a scalar encoded in a string, for the "string" option.
+func (d *decoder) quotedValue(n Node, v reflect.Value) {
+	if _, ok := n.(Null); ok {
+		d.value(n, v)
+		return
+	}
+	s, ok := n.(String)
+	if !ok {
+		d.mismatch(n, v.Type(), nil)
+		return
+	}
+	inner, err := Parse(s.value)
+	if err != nil {
+		d.mismatch(n, v.Type(), err)
+		return
+	}
+	switch inner.(type) {
+	case Object, Array:
+		d.mismatch(n, v.Type(), errors.New("Not a quoted scalar"))
+		return
+	}
+	d.value(inner, v)
+}
+
+func (d *decoder) mapValue(n Node, v reflect.Value) {
+	o, ok := n.(Object)
+	if !ok {
+		d.mismatch(n, v.Type(), nil)
+		return
+	}
+
+	t := v.Type()
+	switch t.Key().Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		if !reflect.PointerTo(t.Key()).Implements(textUnmarshalerType) {
+			d.mismatch(n, t, nil)
+			return
+		}
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(t, len(o.fields)))
+	}
+
+	for _, member := range o.fields {
+		d.push(member.Key)
+		key, err := mapKey(member.Key, t.Key())
+		if err != nil {
+			d.mismatch(NewString(member.Key), t.Key(), err)
+			d.pop()
+			continue
+		}
+
+		elem := reflect.New(t.Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		d.value(member.Value, elem)
+		v.SetMapIndex(key, elem)
+		d.pop()
+	}
+}
+
+func mapKey(key string, t reflect.Type) (reflect.Value, error) {
+	k := reflect.New(t).Elem()
+	if u, ok := k.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return k, u.UnmarshalText([]byte(key))
+	}
+	switch t.Kind() {
+	case reflect.String:
+		k.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, t.Bits())
+		if err != nil {
+			return k, numError(err)
+		}
+		k.SetInt(i)
+	default:
+		u, err := strconv.ParseUint(key, 10, t.Bits())
+		if err != nil {
+			return k, numError(err)
+		}
+		k.SetUint(u)
+	}
+	return k, nil
+}
+
+func numError(err error) error {
+	if ne, ok := err.(*strconv.NumError); ok {
+		return ne.Err
+	}
+	return err
+}
+
+func (d *decoder) sliceValue(n Node, v reflect.Value) {
+	if s, ok := n.(String); ok && v.Type().Elem().Kind() == reflect.Uint8 {
+		b, err := base64.StdEncoding.DecodeString(s.value)
+		if err != nil {
+			d.mismatch(n, v.Type(), err)
+			return
+		}
+		v.SetBytes(b)
+		return
+	}
+
+	a, ok := n.(Array)
+	if !ok {
+		d.mismatch(n, v.Type(), nil)
+		return
+	}
+	if v.IsNil() || v.Cap() < len(a.nodes) {
+		v.Set(reflect.MakeSlice(v.Type(), len(a.nodes), len(a.nodes)))
+	} else {
+		v.SetLen(len(a.nodes))
+	}
+	for i, node := range a.nodes {
+		d.push(strconv.Itoa(i))
+		d.value(node, v.Index(i))
+		d.pop()
+	}
+}
+
+func (d *decoder) arrayValue(n Node, v reflect.Value) {
+	a, ok := n.(Array)
+	if !ok {
+		d.mismatch(n, v.Type(), nil)
+		return
+	}
+	for i := 0; i < v.Len(); i++ {
+		if i >= len(a.nodes) {
+			v.Index(i).SetZero()
+			continue
+		}
+		d.push(strconv.Itoa(i))
+		d.value(a.nodes[i], v.Index(i))
+		d.pop()
+	}
+}
+
+func (d *decoder) scalarValue(n Node, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if b, ok := n.(Boolean); ok {
+			v.SetBool(b.value)
+			return
+		}
+	case reflect.String:
+		if s, ok := n.(String); ok {
+			v.SetString(s.value)
+			return
+		}
+		if num, ok := n.(Number); ok && v.Type() == jsonNumberType {
+			v.SetString(num.Literal())
+			return
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if num, ok := n.(Number); ok {
+			i, err := num.Int64()
+			if err == nil && v.OverflowInt(i) {
+				err = fmt.Errorf("Cannot convert %s to %s: %w", num.Literal(), v.Type(), strconv.ErrRange)
+			}
+			if err != nil {
+				d.mismatch(n, v.Type(), err)
+				return
+			}
+			v.SetInt(i)
+			return
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if num, ok := n.(Number); ok {
+			u, err := num.Uint64()
+			if err == nil && v.OverflowUint(u) {
+				err = fmt.Errorf("Cannot convert %s to %s: %w", num.Literal(), v.Type(), strconv.ErrRange)
+			}
+			if err != nil {
+				d.mismatch(n, v.Type(), err)
+				return
+			}
+			v.SetUint(u)
+			return
+		}
+	case reflect.Float32, reflect.Float64:
+		if num, ok := n.(Number); ok {
+			f, err := num.Float64()
+			if err == nil && v.OverflowFloat(f) {
+				err = fmt.Errorf("Cannot convert %s to %s: %w", num.Literal(), v.Type(), strconv.ErrRange)
+			}
+			if err != nil {
+				d.mismatch(n, v.Type(), err)
+				return
+			}
+			v.SetFloat(f)
+			return
+		}
+	}
+	d.mismatch(n, v.Type(), nil)
+}
+
+// pointerString returns the JSON Pointer of path.
+func pointerString(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
@@ -0,0 +1,396 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type unmarshalInner struct {
+	B int `json:"b"`
+}
+
+type UnmarshalEmbedded struct {
+	E      string
+	Hidden string `json:"hidden"`
+}
+
+type unmarshalEmbedded struct {
+	U int
+}
+
+type unmarshalStruct struct {
+	Name     string           `json:"name"`
+	Renamed  int              `json:"renamed_field,omitempty"`
+	Quoted   int64            `json:"quoted,string"`
+	QuotedS  string           `json:"quoted_s,string"`
+	Skipped  int              `json:"-"`
+	Dash     int              `json:"-,"`
+	Pointer  *unmarshalInner  `json:"pointer"`
+	Slice    []unmarshalInner `json:"slice"`
+	Array    [2]int           `json:"array"`
+	Map      map[string]int   `json:"map"`
+	IntMap   map[int]bool     `json:"int_map"`
+	Any      any              `json:"any"`
+	Bytes    []byte           `json:"bytes"`
+	Float    float32          `json:"float"`
+	Uint     uint8            `json:"uint"`
+	Node     Node             `json:"node"`
+	Object   Object           `json:"object"`
+	Untagged bool
+	private  int
+	UnmarshalEmbedded
+	unmarshalEmbedded
+	Hidden string
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		target  func() any
+		want    any
+		wantErr string
+	}{
+		"struct": {
+			input: `{
+				"name": "a",
+				"renamed_field": 1,
+				"quoted": "123",
+				"quoted_s": "\"q\"",
+				"Skipped": 2,
+				"-": 3,
+				"pointer": {"b": 4},
+				"slice": [{"b": 5}, {"b": 6}],
+				"array": [7],
+				"map": {"x": 8},
+				"int_map": {"-9": true},
+				"any": {"a": [1, "b", true, null]},
+				"bytes": "aGVsbG8=",
+				"float": 1.5,
+				"uint": 255,
+				"node": [1],
+				"object": {"c": 2},
+				"untagged": true,
+				"private": 10,
+				"E": "e",
+				"hidden": "promoted",
+				"Hidden": "outer",
+				"U": 11,
+				"unknown": 12
+			}`,
+			target: func() any { return &unmarshalStruct{} },
+			want: &unmarshalStruct{
+				Name:     "a",
+				Renamed:  1,
+				Quoted:   123,
+				QuotedS:  "q",
+				Dash:     3,
+				Pointer:  &unmarshalInner{4},
+				Slice:    []unmarshalInner{{5}, {6}},
+				Array:    [2]int{7, 0},
+				Map:      map[string]int{"x": 8},
+				IntMap:   map[int]bool{-9: true},
+				Any:      map[string]any{"a": []any{1.0, "b", true, nil}},
+				Bytes:    []byte("hello"),
+				Float:    1.5,
+				Uint:     255,
+				Node:     NewArray(NewNumberFromInt(1)),
+				Object:   NewObject(ObjectField{"c", NewNumberFromInt(2)}),
+				Untagged: true,
+				UnmarshalEmbedded: UnmarshalEmbedded{
+					E:      "e",
+					Hidden: "promoted",
+				},
+				unmarshalEmbedded: unmarshalEmbedded{11},
+				Hidden:            "outer",
+			},
+		},
+		"null": {
+			input: `{"name": null, "pointer": null, "slice": null, "map": null, "any": null}`,
+			target: func() any {
+				return &unmarshalStruct{Name: "a", Pointer: &unmarshalInner{}, Slice: []unmarshalInner{}, Map: map[string]int{}, Any: 1}
+			},
+			want: &unmarshalStruct{Name: "a"},
+		},
+		"merge into existing": {
+			input: `{"pointer": {"b": 2}, "map": {"b": 2}}`,
+			target: func() any {
+				return &unmarshalStruct{Name: "a", Pointer: &unmarshalInner{1}, Map: map[string]int{"a": 1}}
+			},
+			want: &unmarshalStruct{Name: "a", Pointer: &unmarshalInner{2}, Map: map[string]int{"a": 1, "b": 2}},
+		},
+		"case-insensitive keys": {
+			input:  `{"NAME": "a", "Name": "b", "untagged": true}`,
+			target: func() any { return &unmarshalStruct{} },
+			want:   &unmarshalStruct{Name: "b", Untagged: true},
+		},
+		"scalar": {
+			input:  `-12`,
+			target: func() any { return new(int) },
+			want:   ptr(-12),
+		},
+		"pointer to pointer": {
+			input:  `"a"`,
+			target: func() any { return new(*string) },
+			want:   ptr(ptr("a")),
+		},
+		"interface": {
+			input:  `[{"a": 1e2}]`,
+			target: func() any { return new(any) },
+			want:   ptr[any]([]any{map[string]any{"a": 100.0}}),
+		},
+		"interface holding pointer": {
+			input:  `{"b": 1}`,
+			target: func() any { v := any(&unmarshalInner{}); return &v },
+			want:   ptr[any](&unmarshalInner{1}),
+		},
+		"type mismatches": {
+			input: `{
+				"name": 1,
+				"slice": [{"b": "x"}, {"b": 2}, 3],
+				"map": {"a/b": true},
+				"int_map": {"x": true},
+				"uint": 256,
+				"array": [1.5],
+				"untagged": "yes"
+			}`,
+			target: func() any { return &unmarshalStruct{} },
+			want: &unmarshalStruct{
+				Slice:  []unmarshalInner{{}, {2}, {}},
+				Map:    map[string]int{"a/b": 0},
+				IntMap: map[int]bool{},
+			},
+			wantErr: "Cannot unmarshal number into string at /name\n" +
+				"Cannot unmarshal string into int at /slice/0/b\n" +
+				"Cannot unmarshal number into node.unmarshalInner at /slice/2\n" +
+				"Cannot unmarshal boolean into int at /map/a~1b\n" +
+				"Cannot unmarshal string into int at /int_map/x: invalid syntax\n" +
+				"Cannot unmarshal number into uint8 at /uint: Cannot convert 256 to uint8: value out of range\n" +
+				"Cannot unmarshal number into int at /array/0: Cannot convert 1.5 to int64: Not an integer\n" +
+				"Cannot unmarshal string into bool at /untagged",
+		},
+		"root mismatch": {
+			input:   `[]`,
+			target:  func() any { return &unmarshalStruct{} },
+			want:    &unmarshalStruct{},
+			wantErr: "Cannot unmarshal array into node.unmarshalStruct at root",
+		},
+		"quoted mismatch": {
+			input:  `{"quoted": 1, "quoted_s": "q"}`,
+			target: func() any { return &unmarshalStruct{} },
+			want:   &unmarshalStruct{},
+			wantErr: "Cannot unmarshal number into int64 at /quoted\n" +
+				"Cannot unmarshal string into string at /quoted_s: 1:1: Unexpected character: 'q'",
+		},
+		"float overflow": {
+			input:   `3.5e38`,
+			target:  func() any { return new(float32) },
+			want:    new(float32),
+			wantErr: "Cannot unmarshal number into float32 at root: Cannot convert 3.5e38 to float32: value out of range",
+		},
+		"float overflow through pointer": {
+			input:   `{"p": 1e40}`,
+			target:  func() any { return new(struct{ P *float32 }) },
+			want:    new(struct{ P *float32 }),
+			wantErr: "Cannot unmarshal number into float32 at /p: Cannot convert 1e40 to float32: value out of range",
+		},
+		"mismatch inside pointer": {
+			input:   `{"p": {"name": 1, "uint": 2}}`,
+			target:  func() any { return new(struct{ P *unmarshalStruct }) },
+			want:    &struct{ P *unmarshalStruct }{&unmarshalStruct{Uint: 2}},
+			wantErr: "Cannot unmarshal number into string at /p/name",
+		},
+		"bad base64": {
+			input:   `"!"`,
+			target:  func() any { return new([]byte) },
+			want:    new([]byte),
+			wantErr: "Cannot unmarshal string into []uint8 at root: illegal base64 data at input byte 0",
+		},
+		"unsupported map key": {
+			input:   `{}`,
+			target:  func() any { return new(map[float64]int) },
+			want:    new(map[float64]int),
+			wantErr: "Cannot unmarshal object into map[float64]int at root",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.target()
+			err := Decode(tt.input, got)
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Decode(%s) error:\n%v\n(want:\n%v\n)", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("Decode(%s) error: nil (want: %v)", tt.input, tt.wantErr)
+			}
+
+			opts := []cmp.Option{
+				cmp.AllowUnexported(unmarshalStruct{}, Object{}, Array{}, Number{}, objectIndex{}),
+				cmp.Comparer(func(a, b Number) bool { return a.literal == b.literal }),
+			}
+			if diff := cmp.Diff(tt.want, got, opts...); diff != "" {
+				t.Fatalf("Decode(%s) mismatch (-want +got):\n%s", tt.input, diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	n, _ := Parse(`{"a": [1, "x"]}`)
+
+	var v struct{ A []int }
+	err := Unmarshal(n, &v)
+	var typeErr *UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Unmarshal() error: %v (want: *UnmarshalTypeError)", err)
+	}
+	if typeErr.Path != "/a/1" || typeErr.Value != TypeString || typeErr.Type != reflect.TypeFor[int]() {
+		t.Fatalf("Unmarshal() error: %#v", typeErr)
+	}
+
+	for _, target := range []any{nil, v, (*int)(nil)} {
+		if err := Unmarshal(n, target); err == nil {
+			t.Fatalf("Unmarshal(%T) error: nil", target)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// celsius is written as {"celsius": n}.
+type celsius float64
+
+func (c celsius) MarshalNode() (Node, error) {
+	if c < -273.15 {
+		return nil, errors.New("Below absolute zero")
+	}
+	n, err := NewNumberFromFloat(float64(c))
+	return NewObject(ObjectField{"celsius", n}), err
+}
+
+func (c *celsius) UnmarshalNode(n Node) error {
+	var v struct {
+		Celsius *float64 `json:"celsius"`
+	}
+	if err := Unmarshal(n, &v); err != nil {
+		return err
+	}
+	if v.Celsius == nil {
+		return errors.New("Missing celsius")
+	}
+	*c = celsius(*v.Celsius)
+	return nil
+}
+
+// upper is written as an upper-case string.
+type upper string
+
+func (u upper) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(u))), nil
+}
+
+func (u *upper) UnmarshalText(b []byte) error {
+	if strings.ToUpper(string(b)) != string(b) {
+		return fmt.Errorf("Not upper case: %s", b)
+	}
+	*u = upper(strings.ToLower(string(b)))
+	return nil
+}
+
+type hooks struct {
+	Temperature celsius          `json:"temperature"`
+	Pointer     *celsius         `json:"pointer"`
+	Name        upper            `json:"name"`
+	Names       map[upper]upper  `json:"names"`
+	Big         *big.Int         `json:"big"`
+	Time        time.Time        `json:"time"`
+	Optional    *upper           `json:"optional"`
+	Nested      map[string]hooks `json:"nested,omitempty"`
+}
+
+func TestUnmarshal_hooks(t *testing.T) {
+	b, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	c := celsius(-40)
+	value := hooks{
+		Temperature: 21.5,
+		Pointer:     &c,
+		Name:        "abc",
+		Names:       map[upper]upper{"k": "v"},
+		Big:         b,
+		Time:        time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 9*60*60)),
+	}
+	want := `{"temperature":{"celsius":21.5},"pointer":{"celsius":-40},"name":"ABC","names":{"K":"V"},"big":123456789012345678901234567890,"time":"2024-01-02T03:04:05+09:00","optional":null}`
+
+	n, err := FromValue(value)
+	if err != nil {
+		t.Fatalf("FromValue() error: %v", err)
+	}
+	if s := n.String(); s != want {
+		t.Fatalf("FromValue() = %s (want: %s)", s, want)
+	}
+
+	var got hooks
+	if err := Unmarshal(n, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) error: %v", n, err)
+	}
+	opts := cmp.Comparer(func(a, b *big.Int) bool { return a.Cmp(b) == 0 })
+	if diff := cmp.Diff(value, got, opts); diff != "" {
+		t.Fatalf("Unmarshal(%s) mismatch (-want +got):\n%s", n, diff)
+	}
+}
+
+func TestUnmarshal_hookErrors(t *testing.T) {
+	if _, err := FromValue(map[string]celsius{"a": -300}); err == nil ||
+		err.Error() != "Cannot marshal node.celsius at /a: Below absolute zero" {
+		t.Fatalf("FromValue() error: %v", err)
+	}
+
+	tests := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"unmarshaler": {
+			input:   `{"temperature": {}}`,
+			wantErr: "Cannot unmarshal object into node.celsius at /temperature: Missing celsius",
+		},
+		"unmarshaler with mismatch": {
+			input:   `{"nested": {"a": {"pointer": {"celsius": "hot"}}}}`,
+			wantErr: "Cannot unmarshal object into node.celsius at /nested/a/pointer: Cannot unmarshal string into float64 at /celsius",
+		},
+		"text unmarshaler": {
+			input:   `{"name": "abc"}`,
+			wantErr: "Cannot unmarshal string into node.upper at /name: Not upper case: abc",
+		},
+		"text unmarshaler with number": {
+			input:   `{"name": 1}`,
+			wantErr: "Cannot unmarshal number into node.upper at /name",
+		},
+		"text unmarshaler key": {
+			input:   `{"names": {"k": "V"}}`,
+			wantErr: "Cannot unmarshal string into node.upper at /names/k: Not upper case: k",
+		},
+		"json unmarshaler": {
+			input:   `{"time": "yesterday"}`,
+			wantErr: `Cannot unmarshal string into time.Time at /time: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var v hooks
+			err := Decode(tt.input, &v)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Decode(%s) error: %v (want: %v)", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
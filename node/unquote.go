@@ -0,0 +1,72 @@
+package node
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// unquote decodes a string token validated by the tokenizer.
+func unquote(raw string) string {
+	s := raw[1 : len(raw)-1]
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '\\')
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		s = s[i+1:]
+
+		switch s[0] {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			r := unquoteHex(s[1:5])
+			s = s[4:]
+			if 0xD800 <= r && r < 0xDC00 {
+				if r2, ok := unquoteSurrogate(s[1:]); ok {
+					r = utf16.DecodeRune(r, r2)
+					s = s[6:]
+				}
+			}
+			if utf16.IsSurrogate(r) {
+				r = utf8.RuneError
+			}
+			b.WriteRune(r)
+		default: // '"', '\\', '/'
+			b.WriteByte(s[0])
+		}
+		s = s[1:]
+	}
+	return b.String()
+}
+
+func unquoteHex(s string) rune {
+	r, _ := strconv.ParseUint(s, 16, 16)
+	return rune(r)
+}
+
+// unquoteSurrogate reads the "\uXXXX" low surrogate at the start of s.
+func unquoteSurrogate(s string) (rune, bool) {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return 0, false
+	}
+	r := unquoteHex(s[2:6])
+	return r, 0xDC00 <= r && r < 0xE000
+}
@@ -0,0 +1,177 @@
+package node
+
+import (
+	"io"
+
+	"github.com/a-skua/json-parser/node/internal/state"
+	"github.com/a-skua/json-parser/token"
+)
+
+// Handler receives the values of a document as events, in order.
+// An error returned by a method stops the walk.
+type Handler interface {
+	StartObject() error
+	Key(key string) error
+	EndObject() error
+	StartArray() error
+	EndArray() error
+	String(s string) error
+	Number(n Number) error
+	Bool(b bool) error
+	Null() error
+}
+
+// Walk reads input as a single JSON document, as Parse does, and reports
+// its values to h without building nodes. It returns the first error of h
+// as it is. Since the input is checked as it is read, h may have received
+// events before a syntax error is returned.
+func Walk(input string, h Handler, opts ...Option) error {
+	o := newOptions(opts)
+	return walk(NewLexer(token.NewTokenizer(token.Runes(input), o.tokenizer...), opts...), h)
+}
+
+// WalkReader is like Walk but streams its input from r.
+func WalkReader(r io.Reader, h Handler, opts ...Option) error {
+	o := newOptions(opts)
+	return walk(NewLexer(token.NewReaderTokenizer(r, o.tokenizer...), opts...), h)
+}
+
+// WalkBytes is like Walk but reads UTF-8 input without converting it to runes.
+func WalkBytes(input []byte, h Handler, opts ...Option) error {
+	o := newOptions(opts)
+	return walk(NewByteLexer(token.NewByteTokenizer(input, o.tokenizer...), opts...), h)
+}
+
+func walk(lexer Lexer, h Handler) error {
+	if err := lexer.walkValue(h); err != nil {
+		return err
+	}
+	return lexer.end()
+}
+
+// token returns the next token other than whitespace, or ErrEON at the end.
+func (l *Lexer) token() (token.Token, error) {
+	for {
+		t, err := l.tokenizer.Next()
+		if err == token.ErrEOT {
+			return token.Token{}, ErrEON
+		}
+		if err != nil {
+			return token.Token{}, err
+		}
+		if t.Type != token.Whitespace {
+			l.last = t
+			return t, nil
+		}
+	}
+}
+
+func (l *Lexer) walkValue(h Handler) error {
+	t, err := l.token()
+	if err != nil {
+		return l.syntaxError(err, valueTypes...)
+	}
+	return l.walkToken(t, h)
+}
+
+// walkToken reports the value starting with t.
+func (l *Lexer) walkToken(t token.Token, h Handler) error {
+	switch t.Type {
+	case token.String:
+		return h.String(newString(t).value)
+	case token.Number:
+		n, err := newNumber(t, l.opts.number)
+		if err != nil {
+			return err
+		}
+		return h.Number(n)
+	case token.True, token.False:
+		return h.Bool(t.Type == token.True)
+	case token.Null:
+		return h.Null()
+	case token.LeftBracket:
+		return l.walkArray(h)
+	case token.LeftBrace:
+		return l.walkObject(h)
+	default:
+		return l.syntaxError(nil, valueTypes...)
+	}
+}
+
+func (l *Lexer) walkArray(h Handler) error {
+	if err := h.StartArray(); err != nil {
+		return err
+	}
+
+	for state := state.NewArray(); ; state = state.Next() {
+		t, err := l.token()
+		if err != nil {
+			if state.IsValue() {
+				return l.syntaxError(err, valueTypes...)
+			}
+			return l.syntaxError(err, token.Comma, token.RightBracket)
+		}
+		if state.IsSeparator() && t.Type == token.RightBracket {
+			break
+		}
+
+		if state.IsValue() {
+			if err := l.walkToken(t, h); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if t.Type != token.Comma {
+			return l.syntaxError(nil, token.Comma, token.RightBracket)
+		}
+	}
+
+	return h.EndArray()
+}
+
+func (l *Lexer) walkObject(h Handler) error {
+	if err := h.StartObject(); err != nil {
+		return err
+	}
+
+	empty := true
+	for state := state.NewObject(); ; state = state.Next() {
+		t, err := l.token()
+		if state.IsSeparator() && err == nil && t.Type == token.Comma {
+			continue
+		}
+		if state.IsSeparator() && err == nil && t.Type == token.RightBrace {
+			break
+		}
+		if state.IsSeparator() {
+			return l.syntaxError(err, token.Comma, token.RightBrace)
+		}
+		if empty && err == nil && t.Type == token.RightBrace {
+			break
+		}
+
+		if err != nil || t.Type != token.String {
+			if empty {
+				return l.syntaxError(err, token.String, token.RightBrace)
+			}
+			return l.syntaxError(err, token.String)
+		}
+		if err := h.Key(newString(t).value); err != nil {
+			return err
+		}
+		empty = false
+		state = state.Next()
+
+		if t, err = l.token(); err != nil || t.Type != token.Colon {
+			return l.syntaxError(err, token.Colon)
+		}
+		state = state.Next()
+
+		if err := l.walkValue(h); err != nil {
+			return err
+		}
+	}
+
+	return h.EndObject()
+}
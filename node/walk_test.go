@@ -0,0 +1,157 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// recorder records events and fails with its error at the event named stop.
+type recorder struct {
+	events []string
+	stop   string
+	err    error
+}
+
+func (r *recorder) event(e string) error {
+	r.events = append(r.events, e)
+	if e == r.stop {
+		return r.err
+	}
+	return nil
+}
+
+func (r *recorder) StartObject() error    { return r.event("{") }
+func (r *recorder) Key(key string) error  { return r.event("key " + key) }
+func (r *recorder) EndObject() error      { return r.event("}") }
+func (r *recorder) StartArray() error     { return r.event("[") }
+func (r *recorder) EndArray() error       { return r.event("]") }
+func (r *recorder) String(s string) error { return r.event("string " + s) }
+func (r *recorder) Number(n Number) error { return r.event(fmt.Sprintf("number %s %v", n, n.Value())) }
+func (r *recorder) Bool(b bool) error     { return r.event(fmt.Sprintf("bool %v", b)) }
+func (r *recorder) Null() error           { return r.event("null") }
+
+func TestWalk(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		opts  []Option
+		want  []string
+	}{
+		"scalar": {
+			input: ` "a\nb" `,
+			want:  []string{"string a\nb"},
+		},
+		"number": {
+			input: `1e400`,
+			opts:  []Option{NumberAs(RepresentationLiteral)},
+			want:  []string{"number 1e400 1e400"},
+		},
+		"number out of range": {
+			input: `-1e400`,
+			want:  []string{"number -1e400 -Inf"},
+		},
+		"empty": {
+			input: `{"a": [], "b": {}}`,
+			want:  []string{"{", "key a", "[", "]", "key b", "{", "}", "}"},
+		},
+		"nested": {
+			input: `[{"a": 1, "a": [true, false, null]}, "x"]`,
+			want: []string{
+				"[", "{", "key a", "number 1 1",
+				"key a", "[", "bool true", "bool false", "null", "]",
+				"}", "string x", "]",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			walks := map[string]func(*recorder) error{
+				"Walk":       func(r *recorder) error { return Walk(tt.input, r, tt.opts...) },
+				"WalkReader": func(r *recorder) error { return WalkReader(strings.NewReader(tt.input), r, tt.opts...) },
+				"WalkBytes":  func(r *recorder) error { return WalkBytes([]byte(tt.input), r, tt.opts...) },
+			}
+			for walkName, walk := range walks {
+				r := &recorder{}
+				if err := walk(r); err != nil {
+					t.Fatalf("%s(%s) error: %v", walkName, tt.input, err)
+				}
+				if diff := cmp.Diff(tt.want, r.events); diff != "" {
+					t.Fatalf("%s(%s) mismatch (-want +got):\n%s", walkName, tt.input, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestWalk_syntaxErrors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  []string
+	}{
+		"empty input":           {input: ``},
+		"trailing value":        {input: `1 2`, want: []string{"number 1 1"}},
+		"unclosed array":        {input: `[`, want: []string{"["}},
+		"trailing comma":        {input: `[1,]`, want: []string{"[", "number 1 1"}},
+		"missing comma":         {input: `[1 2]`, want: []string{"[", "number 1 1"}},
+		"leading comma":         {input: `[,1]`, want: []string{"["}},
+		"mismatched bracket":    {input: `[}`, want: []string{"["}},
+		"unclosed object":       {input: `{`, want: []string{"{"}},
+		"non-string key":        {input: `{1: 2}`, want: []string{"{"}},
+		"key after comma":       {input: `{"a": 1, }`, want: []string{"{", "key a", "number 1 1"}},
+		"missing colon":         {input: `{"a" 1}`, want: []string{"{", "key a"}},
+		"missing value":         {input: `{"a":}`, want: []string{"{", "key a"}},
+		"missing object comma":  {input: `{"a": 1 "b": 2}`, want: []string{"{", "key a", "number 1 1"}},
+		"invalid token":         {input: `[tru]`, want: []string{"["}},
+		"nested unclosed array": {input: `{"a": [1, {"b": [}`, want: []string{"{", "key a", "[", "number 1 1", "{", "key b", "["}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, want := Parse(tt.input)
+			if want == nil {
+				t.Fatalf("Parse(%s) error: nil", tt.input)
+			}
+
+			r := &recorder{}
+			err := Walk(tt.input, r)
+			if err == nil || err.Error() != want.Error() {
+				t.Fatalf("Walk(%s) error: %v (want: %v)", tt.input, err, want)
+			}
+			if diff := cmp.Diff(tt.want, r.events); diff != "" {
+				t.Fatalf("Walk(%s) mismatch (-want +got):\n%s", tt.input, diff)
+			}
+		})
+	}
+}
+
+func TestWalk_abort(t *testing.T) {
+	errStop := errors.New("Stop")
+	tests := map[string]struct {
+		stop string
+		want []string
+	}{
+		"StartObject": {stop: "{", want: []string{"{"}},
+		"Key":         {stop: "key b", want: []string{"{", "key a", "[", "number 1 1", "]", "key b"}},
+		"EndArray":    {stop: "]", want: []string{"{", "key a", "[", "number 1 1", "]"}},
+		"String":      {stop: "string x", want: []string{"{", "key a", "[", "number 1 1", "]", "key b", "string x"}},
+		"EndObject":   {stop: "}", want: []string{"{", "key a", "[", "number 1 1", "]", "key b", "string x", "}"}},
+	}
+
+	// The input is invalid after the object, which Walk never reaches.
+	input := `{"a": [1], "b": "x"} !`
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{stop: tt.stop, err: errStop}
+			if err := Walk(input, r); err != errStop {
+				t.Fatalf("Walk(%s) error: %v (want: %v)", input, err, errStop)
+			}
+			if diff := cmp.Diff(tt.want, r.events); diff != "" {
+				t.Fatalf("Walk(%s) mismatch (-want +got):\n%s", input, diff)
+			}
+		})
+	}
+}
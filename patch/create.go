@@ -0,0 +1,129 @@
+package patch
+
+import (
+	"strconv"
+
+	"github.com/a-skua/json-parser/internal/align"
+	"github.com/a-skua/json-parser/node"
+	"github.com/a-skua/json-parser/pointer"
+)
+
+// Create returns a patch that transforms a into b.
+// Objects are compared member by member, and arrays are aligned by the
+// fewest element additions, removals and replacements. Arrays whose
+// differing elements are too many to align are patched index by index.
+func Create(a, b node.Node) Patch {
+	return create(Patch{}, pointer.Pointer{}, a, b)
+}
+
+func create(patch Patch, p pointer.Pointer, a, b node.Node) Patch {
+	if node.Equal(a, b) {
+		return patch
+	}
+
+	switch a := a.(type) {
+	case node.Object:
+		if b, ok := b.(node.Object); ok {
+			return createObject(patch, p, a, b)
+		}
+	case node.Array:
+		if b, ok := b.(node.Array); ok {
+			return createArray(patch, p, a, b)
+		}
+	}
+	return append(patch, Operation{Op: OpReplace, Path: p, Value: b})
+}
+
+func createObject(patch Patch, p pointer.Pointer, a, b node.Object) Patch {
+	keys := uniq(a.Keys())
+	for _, key := range keys {
+		if !b.Has(key) {
+			patch = append(patch, Operation{Op: OpRemove, Path: p.Append(key)})
+		}
+	}
+	for _, key := range keys {
+		if y, ok := b.Get(key); ok {
+			x, _ := a.Get(key)
+			patch = create(patch, p.Append(key), x, y)
+		}
+	}
+	for _, key := range uniq(b.Keys()) {
+		if !a.Has(key) {
+			y, _ := b.Get(key)
+			patch = append(patch, Operation{Op: OpAdd, Path: p.Append(key), Value: y})
+		}
+	}
+	return patch
+}
+
+// createArray emits the edit script from the last element backwards, so
+// that no operation shifts the index of a later one.
+func createArray(patch Patch, p pointer.Pointer, a, b node.Array) Patch {
+	arrays := align.Trim(a.Nodes(), b.Nodes())
+	x, y := arrays.X, arrays.Y
+	index := func(i int) string {
+		return strconv.Itoa(arrays.Offset + i)
+	}
+
+	if !arrays.Fits() {
+		for i := len(x) - 1; i >= len(y); i-- {
+			patch = append(patch, Operation{Op: OpRemove, Path: p.Append(index(i))})
+		}
+		for j := len(x); j < len(y); j++ {
+			patch = append(patch, Operation{Op: OpAdd, Path: p.Append(index(j)), Value: y[j]})
+		}
+		for i := min(len(x), len(y)) - 1; i >= 0; i-- {
+			patch = create(patch, p.Append(index(i)), x[i], y[i])
+		}
+		return patch
+	}
+
+	// distance[i][j] is the edit distance between x[:i] and y[:j].
+	distance := make([][]int, len(x)+1)
+	for i := range distance {
+		distance[i] = make([]int, len(y)+1)
+		distance[i][0] = i
+	}
+	for j := range distance[0] {
+		distance[0][j] = j
+	}
+	for i := 1; i <= len(x); i++ {
+		for j := 1; j <= len(y); j++ {
+			if arrays.Equal(i-1, j-1) {
+				distance[i][j] = distance[i-1][j-1]
+				continue
+			}
+			distance[i][j] = 1 + min(distance[i-1][j-1], distance[i-1][j], distance[i][j-1])
+		}
+	}
+
+	i, j := len(x), len(y)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && arrays.Equal(i-1, j-1):
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && distance[i][j] == distance[i-1][j-1]+1:
+			patch = create(patch, p.Append(index(i-1)), x[i-1], y[j-1])
+			i, j = i-1, j-1
+		case i > 0 && distance[i][j] == distance[i-1][j]+1:
+			patch = append(patch, Operation{Op: OpRemove, Path: p.Append(index(i - 1))})
+			i--
+		default:
+			patch = append(patch, Operation{Op: OpAdd, Path: p.Append(index(i)), Value: y[j-1]})
+			j--
+		}
+	}
+	return patch
+}
+
+func uniq(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
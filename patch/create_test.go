@@ -0,0 +1,144 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/a-skua/json-parser/node"
+	"github.com/a-skua/json-parser/pointer"
+)
+
+func TestCreate(t *testing.T) {
+	tests := map[string]struct {
+		a    string
+		b    string
+		want string
+	}{
+		"equal": {
+			a:    `{"a": [1, {"b": 2}]}`,
+			b:    `{"a": [1.0, {"b": 2}]}`,
+			want: `[]`,
+		},
+		"root": {
+			a:    `1`,
+			b:    `"1"`,
+			want: `[{"op":"replace","path":"","value":"1"}]`,
+		},
+		"object members": {
+			a:    `{"a": 1, "b": 2, "c": 3}`,
+			b:    `{"d": 4, "b": 2, "c": 5}`,
+			want: `[{"op":"remove","path":"/a"},{"op":"replace","path":"/c","value":5},{"op":"add","path":"/d","value":4}]`,
+		},
+		"nested": {
+			a:    `{"a": {"b": {"c": 1}}}`,
+			b:    `{"a": {"b": {"c": 2}}}`,
+			want: `[{"op":"replace","path":"/a/b/c","value":2}]`,
+		},
+		"escaped keys": {
+			a:    `{"a/b": 1}`,
+			b:    `{"a/b": 2, "~": 3}`,
+			want: `[{"op":"replace","path":"/a~1b","value":2},{"op":"add","path":"/~0","value":3}]`,
+		},
+		"array insert": {
+			a:    `[1, 2, 3]`,
+			b:    `[1, 4, 2, 3]`,
+			want: `[{"op":"add","path":"/1","value":4}]`,
+		},
+		"array append": {
+			a:    `[1, 2]`,
+			b:    `[1, 2, 3, 4]`,
+			want: `[{"op":"add","path":"/2","value":4},{"op":"add","path":"/2","value":3}]`,
+		},
+		"array remove": {
+			a:    `[1, 2, 3, 4]`,
+			b:    `[2, 4]`,
+			want: `[{"op":"remove","path":"/2"},{"op":"remove","path":"/0"}]`,
+		},
+		"array replace": {
+			a:    `[1, {"a": 1}, 3]`,
+			b:    `[1, {"a": 2}, 3]`,
+			want: `[{"op":"replace","path":"/1/a","value":2}]`,
+		},
+		"array to object": {
+			a:    `{"a": [1]}`,
+			b:    `{"a": {"0": 1}}`,
+			want: `[{"op":"replace","path":"/a","value":{"0":1}}]`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, err := node.Parse(tt.a)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.a, err)
+			}
+			b, err := node.Parse(tt.b)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.b, err)
+			}
+
+			patch := Create(a, b)
+			if s := patch.String(); s != tt.want {
+				t.Fatalf("Create(%s, %s) = %s (want: %s)", tt.a, tt.b, s, tt.want)
+			}
+
+			got, err := patch.Apply(a)
+			if err != nil {
+				t.Fatalf("Apply() error: %v", err)
+			}
+			if !node.Equal(got, b) {
+				t.Fatalf("Apply() = %s (want: %s)", got, b)
+			}
+		})
+	}
+}
+
+func TestCreate_largeArrays(t *testing.T) {
+	numbers := func(n, from int) []node.Node {
+		nodes := make([]node.Node, n)
+		for i := range nodes {
+			nodes[i] = node.NewNumberFromInt(int64(from + i))
+		}
+		return nodes
+	}
+	changed := numbers(20000, 0)
+	changed[12345] = node.NewString("changed")
+
+	tests := map[string]struct {
+		a, b    node.Array
+		wantLen int
+		want    Operation
+	}{
+		"one element changed": {
+			a:       node.NewArray(numbers(20000, 0)...),
+			b:       node.NewArray(changed...),
+			wantLen: 1,
+			want:    Operation{Op: OpReplace, Path: pointer.Pointer{"12345"}, Value: node.NewString("changed")},
+		},
+		"all elements changed": {
+			a:       node.NewArray(numbers(1100, 0)...),
+			b:       node.NewArray(numbers(1000, 5000)...),
+			wantLen: 1100,
+			want:    Operation{Op: OpRemove, Path: pointer.Pointer{"1099"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			patch := Create(tt.a, tt.b)
+			if len(patch) != tt.wantLen {
+				t.Fatalf("Create() returned %d operations (want: %d)", len(patch), tt.wantLen)
+			}
+			if got := patch[0].Node().String(); got != tt.want.Node().String() {
+				t.Fatalf("Create()[0] = %s (want: %s)", got, tt.want.Node())
+			}
+
+			got, err := patch.Apply(tt.a)
+			if err != nil {
+				t.Fatalf("Apply() error: %v", err)
+			}
+			if !node.Equal(got, tt.b) {
+				t.Fatalf("Apply() differs from b")
+			}
+		})
+	}
+}
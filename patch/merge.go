@@ -0,0 +1,61 @@
+package patch
+
+import (
+	"github.com/a-skua/json-parser/node"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) to target.
+// Members that survive keep their position in target, and new members
+// follow in the order of patch.
+func MergePatch(target, patch node.Node) node.Node {
+	p, ok := patch.(node.Object)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(node.Object)
+	if !ok {
+		t = node.NewObject()
+	}
+	for _, field := range p.Fields() {
+		if field.Value.Type() == node.TypeNull {
+			t = removeField(t, field.Key)
+			continue
+		}
+		value, _ := t.Get(field.Key)
+		t = setField(t, field.Key, MergePatch(value, field.Value))
+	}
+	return t
+}
+
+// CreateMergePatch returns a merge patch that transforms a into b.
+// Null members of b cannot be expressed by a merge patch and are removed
+// instead.
+func CreateMergePatch(a, b node.Node) node.Node {
+	x, ok := a.(node.Object)
+	if !ok {
+		return b
+	}
+	y, ok := b.(node.Object)
+	if !ok {
+		return b
+	}
+
+	fields := []node.ObjectField{}
+	for _, key := range uniq(x.Keys()) {
+		if !y.Has(key) {
+			fields = append(fields, node.ObjectField{Key: key, Value: node.NewNull()})
+		}
+	}
+	for _, key := range uniq(y.Keys()) {
+		v, _ := y.Get(key)
+		u, ok := x.Get(key)
+		switch {
+		case !ok:
+			fields = append(fields, node.ObjectField{Key: key, Value: v})
+		case !node.Equal(u, v):
+			fields = append(fields, node.ObjectField{Key: key, Value: CreateMergePatch(u, v)})
+		}
+	}
+	return node.NewObject(fields...)
+}
@@ -0,0 +1,178 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/a-skua/json-parser/node"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := map[string]struct {
+		target string
+		patch  string
+		want   string
+	}{
+		// RFC 7396 appendix A.
+		"replace member": {
+			target: `{"a":"b"}`,
+			patch:  `{"a":"c"}`,
+			want:   `{"a":"c"}`,
+		},
+		"add member": {
+			target: `{"a":"b"}`,
+			patch:  `{"b":"c"}`,
+			want:   `{"a":"b","b":"c"}`,
+		},
+		"remove member": {
+			target: `{"a":"b"}`,
+			patch:  `{"a":null}`,
+			want:   `{}`,
+		},
+		"remove one of members": {
+			target: `{"a":"b","b":"c"}`,
+			patch:  `{"a":null}`,
+			want:   `{"b":"c"}`,
+		},
+		"replace array with string": {
+			target: `{"a":["b"]}`,
+			patch:  `{"a":"c"}`,
+			want:   `{"a":"c"}`,
+		},
+		"replace string with array": {
+			target: `{"a":"c"}`,
+			patch:  `{"a":["b"]}`,
+			want:   `{"a":["b"]}`,
+		},
+		"nested": {
+			target: `{"a":{"b":"c"}}`,
+			patch:  `{"a":{"b":"d","c":null}}`,
+			want:   `{"a":{"b":"d"}}`,
+		},
+		"replace array": {
+			target: `{"a":[{"b":"c"}]}`,
+			patch:  `{"a":[1]}`,
+			want:   `{"a":[1]}`,
+		},
+		"array target": {
+			target: `["a","b"]`,
+			patch:  `["c","d"]`,
+			want:   `["c","d"]`,
+		},
+		"array target with object patch": {
+			target: `{"a":"b"}`,
+			patch:  `["c"]`,
+			want:   `["c"]`,
+		},
+		"null patch": {
+			target: `{"a":"foo"}`,
+			patch:  `null`,
+			want:   `null`,
+		},
+		"string patch": {
+			target: `{"a":"foo"}`,
+			patch:  `"bar"`,
+			want:   `"bar"`,
+		},
+		"null member": {
+			target: `{"e":null}`,
+			patch:  `{"a":1}`,
+			want:   `{"e":null,"a":1}`,
+		},
+		"scalar target": {
+			target: `[1,2]`,
+			patch:  `{"a":"b","c":null}`,
+			want:   `{"a":"b"}`,
+		},
+		"new nested object": {
+			target: `{}`,
+			patch:  `{"a":{"bb":{"ccc":null}}}`,
+			want:   `{"a":{"bb":{}}}`,
+		},
+		"field order": {
+			target: `{"a":1,"b":2,"c":3,"d":4}`,
+			patch:  `{"e":5,"c":null,"a":0}`,
+			want:   `{"a":0,"b":2,"d":4,"e":5}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			target, err := node.Parse(tt.target)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.target, err)
+			}
+			patch, err := node.Parse(tt.patch)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.patch, err)
+			}
+
+			got := MergePatch(target, patch)
+			if s := got.String(); s != tt.want {
+				t.Fatalf("MergePatch(%s, %s) = %s (want: %s)", tt.target, tt.patch, s, tt.want)
+			}
+			if s := target.String(); s != tt.target {
+				t.Fatalf("MergePatch modified the target: %s (want: %s)", s, tt.target)
+			}
+		})
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	tests := map[string]struct {
+		a    string
+		b    string
+		want string
+	}{
+		"equal": {
+			a:    `{"a":1,"b":[1]}`,
+			b:    `{"b":[1.0],"a":1}`,
+			want: `{}`,
+		},
+		"members": {
+			a:    `{"a":1,"b":2,"c":3}`,
+			b:    `{"a":1,"c":4,"d":5}`,
+			want: `{"b":null,"c":4,"d":5}`,
+		},
+		"nested": {
+			a:    `{"a":{"b":1,"c":2}}`,
+			b:    `{"a":{"b":1}}`,
+			want: `{"a":{"c":null}}`,
+		},
+		"array": {
+			a:    `{"a":[1,2]}`,
+			b:    `{"a":[1]}`,
+			want: `{"a":[1]}`,
+		},
+		"object to scalar": {
+			a:    `{"a":1}`,
+			b:    `true`,
+			want: `true`,
+		},
+		"scalar to object": {
+			a:    `[1]`,
+			b:    `{"a":1}`,
+			want: `{"a":1}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, err := node.Parse(tt.a)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.a, err)
+			}
+			b, err := node.Parse(tt.b)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.b, err)
+			}
+
+			patch := CreateMergePatch(a, b)
+			if s := patch.String(); s != tt.want {
+				t.Fatalf("CreateMergePatch(%s, %s) = %s (want: %s)", tt.a, tt.b, s, tt.want)
+			}
+			if got := MergePatch(a, patch); !node.Equal(got, b) {
+				t.Fatalf("MergePatch() = %s (want: %s)", got, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+// Package patch implements JSON Patch (RFC 6902) over node trees.
+package patch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/a-skua/json-parser/node"
+	"github.com/a-skua/json-parser/pointer"
+)
+
+var (
+	ErrInvalidPatch     = errors.New("Invalid patch")
+	ErrInvalidOperation = errors.New("Invalid operation")
+	ErrTestFailed       = errors.New("Test failed")
+	ErrMoveIntoChild    = errors.New("Cannot move a value into one of its children")
+	ErrRemoveRoot       = errors.New("Cannot remove the root")
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// Error reports the operation of a Patch that could not be parsed or applied.
+type Error struct {
+	Index int
+	Op    string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	if e.Op == "" {
+		return fmt.Sprintf("Operation %d: %v", e.Index, e.Err)
+	}
+	return fmt.Sprintf("Operation %d (%s): %v", e.Index, e.Op, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Operation is a single operation. From is used by move and copy,
+// Value by add, replace and test.
+type Operation struct {
+	Op    string
+	Path  pointer.Pointer
+	From  pointer.Pointer
+	Value node.Node
+}
+
+// Node returns the operation as a patch document member.
+func (o Operation) Node() node.Node {
+	fields := []node.ObjectField{
+		{Key: "op", Value: node.NewString(o.Op)},
+	}
+	if o.Op == OpMove || o.Op == OpCopy {
+		fields = append(fields, node.ObjectField{Key: "from", Value: node.NewString(o.From.String())})
+	}
+	fields = append(fields, node.ObjectField{Key: "path", Value: node.NewString(o.Path.String())})
+	if o.Op == OpAdd || o.Op == OpReplace || o.Op == OpTest {
+		fields = append(fields, node.ObjectField{Key: "value", Value: o.Value})
+	}
+	return node.NewObject(fields...)
+}
+
+type Patch []Operation
+
+// Parse parses a patch document.
+func Parse(input string, opts ...node.Option) (Patch, error) {
+	document, err := node.Parse(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return FromNode(document)
+}
+
+// FromNode reads a patch from an array of operation objects.
+func FromNode(document node.Node) (Patch, error) {
+	array, ok := document.(node.Array)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an array", ErrInvalidPatch)
+	}
+
+	patch := make(Patch, 0, array.Len())
+	for i := 0; i < array.Len(); i++ {
+		n, _ := array.At(i)
+		op, err := parseOperation(n)
+		if err != nil {
+			return nil, &Error{i, op.Op, err}
+		}
+		patch = append(patch, op)
+	}
+	return patch, nil
+}
+
+func parseOperation(n node.Node) (Operation, error) {
+	object, ok := n.(node.Object)
+	if !ok {
+		return Operation{}, fmt.Errorf("%w: not an object", ErrInvalidOperation)
+	}
+
+	var op Operation
+	name, err := member(object, "op")
+	if err != nil {
+		return op, err
+	}
+	op.Op = name
+
+	switch op.Op {
+	case OpAdd, OpRemove, OpReplace, OpMove, OpCopy, OpTest:
+	default:
+		return Operation{}, fmt.Errorf("%w: unknown op %s", ErrInvalidOperation, node.Quote(op.Op, 0))
+	}
+
+	if op.Path, err = pointerMember(object, "path"); err != nil {
+		return op, err
+	}
+	if op.Op == OpMove || op.Op == OpCopy {
+		if op.From, err = pointerMember(object, "from"); err != nil {
+			return op, err
+		}
+	}
+	if op.Op == OpAdd || op.Op == OpReplace || op.Op == OpTest {
+		value, ok := object.Get("value")
+		if !ok {
+			return op, fmt.Errorf("%w: missing \"value\"", ErrInvalidOperation)
+		}
+		op.Value = value
+	}
+	return op, nil
+}
+
+func member(object node.Object, key string) (string, error) {
+	n, ok := object.Get(key)
+	if !ok {
+		return "", fmt.Errorf("%w: missing %s", ErrInvalidOperation, node.Quote(key, 0))
+	}
+	s, ok := n.(node.String)
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not a string", ErrInvalidOperation, node.Quote(key, 0))
+	}
+	return s.Value().(string), nil
+}
+
+func pointerMember(object node.Object, key string) (pointer.Pointer, error) {
+	s, err := member(object, key)
+	if err != nil {
+		return nil, err
+	}
+	return pointer.Parse(s)
+}
+
+// Node returns the patch document.
+func (p Patch) Node() node.Node {
+	nodes := make([]node.Node, len(p))
+	for i, op := range p {
+		nodes[i] = op.Node()
+	}
+	return node.NewArray(nodes...)
+}
+
+func (p Patch) String() string {
+	return p.Node().String()
+}
+
+// Apply returns the result of applying every operation to document.
+// Apply is atomic: on error no result is returned, and document itself is
+// never modified.
+func (p Patch) Apply(document node.Node) (node.Node, error) {
+	for i, op := range p {
+		var err error
+		document, err = op.apply(document)
+		if err != nil {
+			return nil, &Error{i, op.Op, err}
+		}
+	}
+	return document, nil
+}
+
+func (o Operation) apply(document node.Node) (node.Node, error) {
+	switch o.Op {
+	case OpAdd:
+		return add(document, o.Path, o.Value)
+	case OpRemove:
+		document, _, err := remove(document, o.Path)
+		return document, err
+	case OpReplace:
+		if _, err := o.Path.Resolve(document); err != nil {
+			return nil, err
+		}
+		return set(document, o.Path, o.Value, false)
+	case OpMove:
+		if isProperPrefix(o.From, o.Path) {
+			return nil, ErrMoveIntoChild
+		}
+		document, value, err := remove(document, o.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(document, o.Path, value)
+	case OpCopy:
+		value, err := o.From.Resolve(document)
+		if err != nil {
+			return nil, err
+		}
+		return add(document, o.Path, value)
+	case OpTest:
+		value, err := o.Path.Resolve(document)
+		if err != nil {
+			return nil, err
+		}
+		if !node.Equal(value, o.Value) {
+			return nil, fmt.Errorf("%w: %s", ErrTestFailed, o.Path)
+		}
+		return document, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %s", ErrInvalidOperation, node.Quote(o.Op, 0))
+	}
+}
+
+func isProperPrefix(prefix, p pointer.Pointer) bool {
+	if len(prefix) >= len(p) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != p[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func add(document node.Node, p pointer.Pointer, value node.Node) (node.Node, error) {
+	return set(document, p, value, true)
+}
+
+// set returns a copy of document with the value at p replaced, or inserted
+// into its parent if insert.
+func set(document node.Node, p pointer.Pointer, value node.Node, insert bool) (node.Node, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+	return update(document, p, func(parent node.Node, token string) (node.Node, error) {
+		switch parent := parent.(type) {
+		case node.Object:
+			return setField(parent, token, value), nil
+		case node.Array:
+			nodes := parent.Nodes()
+			if insert && token == "-" {
+				return node.NewArray(append(nodes[:len(nodes):len(nodes)], value)...), nil
+			}
+			i, err := pointer.ArrayIndex(token)
+			if err != nil {
+				return nil, err
+			}
+			if !insert {
+				if len(nodes) <= i {
+					return nil, pointer.ErrOutOfRange
+				}
+				result := append([]node.Node{}, nodes...)
+				result[i] = value
+				return node.NewArray(result...), nil
+			}
+			if len(nodes) < i {
+				return nil, pointer.ErrOutOfRange
+			}
+			result := make([]node.Node, 0, len(nodes)+1)
+			result = append(append(append(result, nodes[:i]...), value), nodes[i:]...)
+			return node.NewArray(result...), nil
+		default:
+			return nil, pointer.ErrNotContainer
+		}
+	})
+}
+
+// remove returns a copy of document without the value at p, and that value.
+func remove(document node.Node, p pointer.Pointer) (node.Node, node.Node, error) {
+	if len(p) == 0 {
+		return nil, nil, ErrRemoveRoot
+	}
+	value, err := p.Resolve(document)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	document, err = update(document, p, func(parent node.Node, token string) (node.Node, error) {
+		switch parent := parent.(type) {
+		case node.Object:
+			return removeField(parent, token), nil
+		case node.Array:
+			i, _ := pointer.ArrayIndex(token)
+			nodes := parent.Nodes()
+			result := make([]node.Node, 0, len(nodes)-1)
+			return node.NewArray(append(append(result, nodes[:i]...), nodes[i+1:]...)...), nil
+		default:
+			return nil, pointer.ErrNotContainer
+		}
+	})
+	return document, value, err
+}
+
+// update rebuilds the ancestors of p, a non-root pointer, after change returns
+// the new parent of its last token.
+func update(document node.Node, p pointer.Pointer, change func(parent node.Node, token string) (node.Node, error)) (node.Node, error) {
+	parentPointer, token, _ := p.Parent()
+	parent, err := parentPointer.Resolve(document)
+	if err, ok := err.(*pointer.Error); ok {
+		err.Pointer = p
+		return nil, err
+	}
+	child, err := change(parent, token)
+	if err != nil {
+		return nil, &pointer.Error{Pointer: p, Index: len(p) - 1, Err: err}
+	}
+
+	for len(parentPointer) > 0 {
+		parentPointer, token, _ = parentPointer.Parent()
+		parent, _ = parentPointer.Resolve(document)
+		switch p := parent.(type) {
+		case node.Object:
+			child = setField(p, token, child)
+		case node.Array:
+			i, _ := pointer.ArrayIndex(token)
+			nodes := p.Nodes()
+			nodes[i] = child
+			child = node.NewArray(nodes...)
+		}
+	}
+	return child, nil
+}
+
+// setField returns a copy of object with the value of key replaced in place,
+// or appended if key is new. Duplicates of key are dropped.
+func setField(object node.Object, key string, value node.Node) node.Object {
+	fields := object.Fields()
+	result := make([]node.ObjectField, 0, len(fields)+1)
+	found := false
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key != key {
+			result = append(result, fields[i])
+		} else if !found {
+			result = append(result, node.ObjectField{Key: key, Value: value})
+			found = true
+		}
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	if !found {
+		result = append(result, node.ObjectField{Key: key, Value: value})
+	}
+	return node.NewObject(result...)
+}
+
+// removeField returns a copy of object without key.
+func removeField(object node.Object, key string) node.Object {
+	fields := object.Fields()
+	result := make([]node.ObjectField, 0, len(fields))
+	for _, field := range fields {
+		if field.Key != key {
+			result = append(result, field)
+		}
+	}
+	return node.NewObject(result...)
+}
@@ -0,0 +1,281 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/a-skua/json-parser/node"
+	"github.com/a-skua/json-parser/pointer"
+)
+
+func TestPatch_Apply(t *testing.T) {
+	tests := map[string]struct {
+		document string
+		patch    string
+		want     string
+		wantErr  string
+	}{
+		"add object member": {
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:     `{"foo":"bar","baz":"qux"}`,
+		},
+		"add array element": {
+			document: `{"foo": ["bar", "baz"]}`,
+			patch:    `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:     `{"foo":["bar","qux","baz"]}`,
+		},
+		"add to the end of array": {
+			document: `{"foo": ["bar"]}`,
+			patch:    `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			want:     `{"foo":["bar",["abc","def"]]}`,
+		},
+		"add existing member": {
+			document: `{"foo": "bar", "baz": 1}`,
+			patch:    `[{"op": "add", "path": "/foo", "value": null}]`,
+			want:     `{"foo":null,"baz":1}`,
+		},
+		"add root": {
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "", "value": [1]}]`,
+			want:     `[1]`,
+		},
+		"remove object member": {
+			document: `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": "/baz"}]`,
+			want:     `{"foo":"bar"}`,
+		},
+		"remove array element": {
+			document: `{"foo": ["bar", "qux", "baz"]}`,
+			patch:    `[{"op": "remove", "path": "/foo/1"}]`,
+			want:     `{"foo":["bar","baz"]}`,
+		},
+		"replace": {
+			document: `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			want:     `{"baz":"boo","foo":"bar"}`,
+		},
+		"move": {
+			document: `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:     `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		"move array element": {
+			document: `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch:    `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			want:     `{"foo":["all","cows","eat","grass"]}`,
+		},
+		"copy": {
+			document: `{"foo": {"bar": 1}}`,
+			patch:    `[{"op": "copy", "from": "/foo", "path": "/baz"}]`,
+			want:     `{"foo":{"bar":1},"baz":{"bar":1}}`,
+		},
+		"test": {
+			document: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[
+				{"op": "test", "path": "/baz", "value": "qux"},
+				{"op": "test", "path": "/foo/1", "value": 2}
+			]`,
+			want: `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		"test numbers and member order": {
+			document: `{"a": {"x": 1, "y": [1.0]}}`,
+			patch:    `[{"op": "test", "path": "/a", "value": {"y": [1e0], "x": 10e-1}}]`,
+			want:     `{"a":{"x":1,"y":[1.0]}}`,
+		},
+		"escaped paths": {
+			document: `{"/": 0, "~": 1}`,
+			patch:    `[{"op": "test", "path": "/~1", "value": 0}, {"op": "replace", "path": "/~0", "value": 2}]`,
+			want:     `{"/":0,"~":2}`,
+		},
+		"test failed": {
+			document: `{"baz": "qux"}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			wantErr:  "Operation 0 (test): Test failed: /baz",
+		},
+		"test string not number": {
+			document: `{"baz": "1"}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": 1}]`,
+			wantErr:  "Operation 0 (test): Test failed: /baz",
+		},
+		"add to nonexistent parent": {
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			wantErr:  "Operation 0 (add): Key not found: /baz (in /baz/bat)",
+		},
+		"add out of range": {
+			document: `{"foo": [1]}`,
+			patch:    `[{"op": "add", "path": "/foo/2", "value": 2}]`,
+			wantErr:  "Operation 0 (add): Array index out of range: /foo/2 (in /foo/2)",
+		},
+		"add to scalar": {
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "add", "path": "/foo/a", "value": 2}]`,
+			wantErr:  "Operation 0 (add): Value is neither object nor array: /foo/a (in /foo/a)",
+		},
+		"remove nonexistent": {
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "remove", "path": "/bar"}]`,
+			wantErr:  "Operation 0 (remove): Key not found: /bar (in /bar)",
+		},
+		"remove root": {
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "remove", "path": ""}]`,
+			wantErr:  "Operation 0 (remove): Cannot remove the root",
+		},
+		"replace nonexistent": {
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "replace", "path": "/bar", "value": 2}]`,
+			wantErr:  "Operation 0 (replace): Key not found: /bar (in /bar)",
+		},
+		"move into child": {
+			document: `{"foo": {"bar": 1}}`,
+			patch:    `[{"op": "move", "from": "/foo", "path": "/foo/bar/baz"}]`,
+			wantErr:  "Operation 0 (move): Cannot move a value into one of its children",
+		},
+		"leading zero index": {
+			document: `{"foo": [1, 2]}`,
+			patch:    `[{"op": "replace", "path": "/foo/01", "value": 3}]`,
+			wantErr:  "Operation 0 (replace): Invalid array index: /foo/01 (in /foo/01)",
+		},
+		"error after changes": {
+			document: `{"foo": 1}`,
+			patch: `[
+				{"op": "add", "path": "/bar", "value": 2},
+				{"op": "test", "path": "/foo", "value": 2}
+			]`,
+			wantErr: "Operation 1 (test): Test failed: /foo",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			document, err := node.Parse(tt.document)
+			if err != nil {
+				t.Fatalf("node.Parse(%s) error: %v", tt.document, err)
+			}
+			patch, err := Parse(tt.patch)
+			if err != nil {
+				t.Fatalf("Parse(%s) error: %v", tt.patch, err)
+			}
+
+			before := document.String()
+			got, err := patch.Apply(document)
+			if after := document.String(); after != before {
+				t.Fatalf("Apply modified the document: %s (want: %s)", after, before)
+			}
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Apply() error: %v (want: %v)", err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("Apply() error: nil (want: %v)", tt.wantErr)
+			}
+			if err != nil {
+				if got != nil {
+					t.Fatalf("Apply() = %v on error (want: nil)", got)
+				}
+				return
+			}
+
+			if s := got.String(); s != tt.want {
+				t.Fatalf("Apply() = %s (want: %s)", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatch_Apply_errors(t *testing.T) {
+	document, _ := node.Parse(`{"foo": [1]}`)
+	_, err := Patch{{Op: OpRemove, Path: pointer.New("foo", "3")}}.Apply(document)
+
+	var patchErr *Error
+	if !errors.As(err, &patchErr) || patchErr.Index != 0 {
+		t.Fatalf("Apply() error: %v (want: *Error at 0)", err)
+	}
+	var pointerErr *pointer.Error
+	if !errors.As(err, &pointerErr) || pointerErr.Token() != "3" {
+		t.Fatalf("Apply() error: %v (want: *pointer.Error at 3)", err)
+	}
+	if !errors.Is(err, pointer.ErrOutOfRange) {
+		t.Fatalf("Apply() error: %v (want: %v)", err, pointer.ErrOutOfRange)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		"operations": {
+			input: `[
+				{"path": "/a", "op": "add", "value": 1, "extra": true},
+				{"op": "remove", "path": "/b"},
+				{"op": "replace", "path": "", "value": null},
+				{"op": "move", "from": "/c", "path": "/d"},
+				{"op": "copy", "from": "/e", "path": "/f"},
+				{"op": "test", "path": "/a~1b", "value": [1]}
+			]`,
+			want: `[{"op":"add","path":"/a","value":1},{"op":"remove","path":"/b"},{"op":"replace","path":"","value":null},{"op":"move","from":"/c","path":"/d"},{"op":"copy","from":"/e","path":"/f"},{"op":"test","path":"/a~1b","value":[1]}]`,
+		},
+		"empty": {
+			input: `[]`,
+			want:  `[]`,
+		},
+		"not an array": {
+			input:   `{"op": "add"}`,
+			wantErr: "Invalid patch: not an array",
+		},
+		"not an object": {
+			input:   `[1]`,
+			wantErr: "Operation 0: Invalid operation: not an object",
+		},
+		"missing op": {
+			input:   `[{"path": "/a"}]`,
+			wantErr: `Operation 0: Invalid operation: missing "op"`,
+		},
+		"unknown op": {
+			input:   `[{"op": "merge", "path": "/a"}]`,
+			wantErr: `Operation 0: Invalid operation: unknown op "merge"`,
+		},
+		"missing path": {
+			input:   `[{"op": "remove"}]`,
+			wantErr: `Operation 0 (remove): Invalid operation: missing "path"`,
+		},
+		"path not string": {
+			input:   `[{"op": "remove", "path": 1}]`,
+			wantErr: `Operation 0 (remove): Invalid operation: "path" is not a string`,
+		},
+		"invalid path": {
+			input:   `[{"op": "remove", "path": "a"}]`,
+			wantErr: `Operation 0 (remove): Invalid pointer: "a"`,
+		},
+		"missing from": {
+			input:   `[{"op": "add", "path": "/a", "value": 1}, {"op": "copy", "path": "/a"}]`,
+			wantErr: `Operation 1 (copy): Invalid operation: missing "from"`,
+		},
+		"missing value": {
+			input:   `[{"op": "test", "path": "/a"}]`,
+			wantErr: `Operation 0 (test): Invalid operation: missing "value"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Parse(%s) error: %v (want: %v)", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("Parse(%s) error: nil (want: %v)", tt.input, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if s := got.String(); s != tt.want {
+				t.Fatalf("Parse(%s) = %s (want: %s)", tt.input, s, tt.want)
+			}
+		})
+	}
+}
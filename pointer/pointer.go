@@ -0,0 +1,192 @@
+// Package pointer implements JSON Pointer (RFC 6901) over node trees.
+package pointer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/a-skua/json-parser/node"
+)
+
+var (
+	ErrSyntax       = errors.New("Invalid pointer")
+	ErrNotFound     = errors.New("Key not found")
+	ErrInvalidIndex = errors.New("Invalid array index")
+	ErrOutOfRange   = errors.New("Array index out of range")
+	ErrNotContainer = errors.New("Value is neither object nor array")
+)
+
+// Error reports the reference token of Pointer at which resolution failed.
+type Error struct {
+	Pointer Pointer
+	Index   int
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Index < 0 || len(e.Pointer) <= e.Index {
+		return fmt.Sprintf("%v (in %s)", e.Err, e.Pointer)
+	}
+	return fmt.Sprintf("%v: %s (in %s)", e.Err, e.Pointer[:e.Index+1], e.Pointer)
+}
+
+// Token returns the reference token at which resolution failed.
+func (e *Error) Token() string {
+	if e.Index < 0 || len(e.Pointer) <= e.Index {
+		return ""
+	}
+	return e.Pointer[e.Index]
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Pointer is a sequence of unescaped reference tokens.
+// The empty Pointer refers to the whole document.
+type Pointer []string
+
+func New(tokens ...string) Pointer {
+	return Pointer(tokens)
+}
+
+func Parse(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("%w: %s", ErrSyntax, strconv.Quote(s))
+	}
+
+	tokens := strings.Split(s[1:], "/")
+	for i, token := range tokens {
+		unescaped, err := Unescape(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, strconv.Quote(s))
+		}
+		tokens[i] = unescaped
+	}
+	return Pointer(tokens), nil
+}
+
+// MustParse is like Parse but panics on an invalid pointer.
+func MustParse(s string) Pointer {
+	p, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Escape encodes '~' as "~0" and '/' as "~1".
+func Escape(token string) string {
+	if !strings.ContainsAny(token, "~/") {
+		return token
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func Unescape(token string) (string, error) {
+	if !strings.Contains(token, "~") {
+		return token, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(token); i++ {
+		if token[i] != '~' {
+			b.WriteByte(token[i])
+			continue
+		}
+		if i+1 == len(token) || (token[i+1] != '0' && token[i+1] != '1') {
+			return "", ErrSyntax
+		}
+		if token[i+1] == '0' {
+			b.WriteByte('~')
+		} else {
+			b.WriteByte('/')
+		}
+		i++
+	}
+	return b.String(), nil
+}
+
+func (p Pointer) String() string {
+	var b strings.Builder
+	for _, token := range p {
+		b.WriteByte('/')
+		b.WriteString(Escape(token))
+	}
+	return b.String()
+}
+
+// Append returns a new Pointer with tokens added.
+func (p Pointer) Append(tokens ...string) Pointer {
+	return append(append(make(Pointer, 0, len(p)+len(tokens)), p...), tokens...)
+}
+
+// Parent returns the pointer without its last token and that token.
+// The root has no parent.
+func (p Pointer) Parent() (Pointer, string, bool) {
+	if len(p) == 0 {
+		return nil, "", false
+	}
+	return p[:len(p)-1], p[len(p)-1], true
+}
+
+// Resolve returns the node p refers to in document.
+func (p Pointer) Resolve(document node.Node) (node.Node, error) {
+	current := document
+	for i, token := range p {
+		next, err := Child(current, token)
+		if err != nil {
+			return nil, &Error{p, i, err}
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// Child returns the member token of an Object or the element token of an Array.
+func Child(parent node.Node, token string) (node.Node, error) {
+	switch parent := parent.(type) {
+	case node.Object:
+		if child, ok := parent.Get(token); ok {
+			return child, nil
+		}
+		return nil, ErrNotFound
+	case node.Array:
+		i, err := ArrayIndex(token)
+		if err != nil {
+			return nil, err
+		}
+		if child, ok := parent.At(i); ok {
+			return child, nil
+		}
+		return nil, ErrOutOfRange
+	default:
+		return nil, ErrNotContainer
+	}
+}
+
+// ArrayIndex parses an array index token: "0" or digits without leading zeros.
+// "-", the index after the last element, is reported as ErrOutOfRange.
+func ArrayIndex(token string) (int, error) {
+	if token == "-" {
+		return 0, ErrOutOfRange
+	}
+	if token == "" || (token[0] == '0' && len(token) > 1) {
+		return 0, ErrInvalidIndex
+	}
+	for _, c := range token {
+		if c < '0' || '9' < c {
+			return 0, ErrInvalidIndex
+		}
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, ErrOutOfRange
+	}
+	return i, nil
+}
@@ -0,0 +1,205 @@
+package pointer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/a-skua/json-parser/node"
+	"github.com/google/go-cmp/cmp"
+)
+
+// document is the example of RFC 6901 section 5.
+const document = `{
+  "foo": ["bar", "baz"],
+  "": 0,
+  "a/b": 1,
+  "c%d": 2,
+  "e^f": 3,
+  "g|h": 4,
+  "i\\j": 5,
+  "k\"l": 6,
+  " ": 7,
+  "m~n": 8
+}`
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    Pointer
+		wantErr string
+	}{
+		"root": {
+			input: "",
+			want:  Pointer{},
+		},
+		"empty key": {
+			input: "/",
+			want:  Pointer{""},
+		},
+		"tokens": {
+			input: "/foo/0",
+			want:  Pointer{"foo", "0"},
+		},
+		"escapes": {
+			input: "/a~1b/m~0n/~01",
+			want:  Pointer{"a/b", "m~n", "~1"},
+		},
+		"no leading slash": {
+			input:   "foo",
+			wantErr: `Invalid pointer: "foo"`,
+		},
+		"invalid escape": {
+			input:   "/a~2",
+			wantErr: `Invalid pointer: "/a~2"`,
+		},
+		"trailing tilde": {
+			input:   "/a~",
+			wantErr: `Invalid pointer: "/a~"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Parse(%s) error: %v (want: %v)", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("Parse(%s) error: nil (want: %v)", tt.input, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("Parse(%s) mismatch (-want +got):\n%s", tt.input, diff)
+			}
+			if s := got.String(); s != tt.input {
+				t.Fatalf("Pointer.String() = %s (want: %s)", s, tt.input)
+			}
+		})
+	}
+}
+
+func TestPointer_Resolve(t *testing.T) {
+	tests := map[string]struct {
+		input     string
+		want      string
+		wantErr   string
+		wantIs    error
+		wantToken string
+	}{
+		"whole document": {
+			input: "",
+			want:  `{"foo":["bar","baz"],"":0,"a/b":1,"c%d":2,"e^f":3,"g|h":4,"i\\j":5,"k\"l":6," ":7,"m~n":8}`,
+		},
+		"/foo": {
+			input: "/foo",
+			want:  `["bar","baz"]`,
+		},
+		"/foo/0": {
+			input: "/foo/0",
+			want:  `"bar"`,
+		},
+		"/": {
+			input: "/",
+			want:  "0",
+		},
+		"/a~1b": {
+			input: "/a~1b",
+			want:  "1",
+		},
+		"/c%d": {
+			input: "/c%d",
+			want:  "2",
+		},
+		"/e^f": {
+			input: "/e^f",
+			want:  "3",
+		},
+		"/g|h": {
+			input: "/g|h",
+			want:  "4",
+		},
+		"/i\\j": {
+			input: "/i\\j",
+			want:  "5",
+		},
+		"/k\"l": {
+			input: "/k\"l",
+			want:  "6",
+		},
+		"/ ": {
+			input: "/ ",
+			want:  "7",
+		},
+		"/m~0n": {
+			input: "/m~0n",
+			want:  "8",
+		},
+		"scalar": {
+			input:     "/foo/0/bar",
+			wantErr:   "Value is neither object nor array: /foo/0/bar (in /foo/0/bar)",
+			wantIs:    ErrNotContainer,
+			wantToken: "bar",
+		},
+		"missing key": {
+			input:     "/bar/0",
+			wantErr:   "Key not found: /bar (in /bar/0)",
+			wantIs:    ErrNotFound,
+			wantToken: "bar",
+		},
+		"index out of range": {
+			input:     "/foo/2",
+			wantErr:   "Array index out of range: /foo/2 (in /foo/2)",
+			wantIs:    ErrOutOfRange,
+			wantToken: "2",
+		},
+		"index after last element": {
+			input:     "/foo/-",
+			wantErr:   "Array index out of range: /foo/- (in /foo/-)",
+			wantIs:    ErrOutOfRange,
+			wantToken: "-",
+		},
+		"leading zero": {
+			input:     "/foo/01",
+			wantErr:   "Invalid array index: /foo/01 (in /foo/01)",
+			wantIs:    ErrInvalidIndex,
+			wantToken: "01",
+		},
+		"not an index": {
+			input:     "/foo/bar",
+			wantErr:   "Invalid array index: /foo/bar (in /foo/bar)",
+			wantIs:    ErrInvalidIndex,
+			wantToken: "bar",
+		},
+	}
+
+	doc, err := node.Parse(document)
+	if err != nil {
+		t.Fatalf("node.Parse() error: %v", err)
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := MustParse(tt.input).Resolve(doc)
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Resolve(%s) error: %v (want: %v)", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("Resolve(%s) error: nil (want: %v)", tt.input, tt.wantErr)
+			}
+			if err != nil {
+				var perr *Error
+				if !errors.As(err, &perr) || !errors.Is(err, tt.wantIs) || perr.Token() != tt.wantToken {
+					t.Fatalf("Resolve(%s) error: %#v (want: %v at %s)", tt.input, err, tt.wantIs, tt.wantToken)
+				}
+				return
+			}
+
+			if got.String() != tt.want {
+				t.Fatalf("Resolve(%s) = %v (want: %v)", tt.input, got, tt.want)
+			}
+		})
+	}
+}
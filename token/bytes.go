@@ -0,0 +1,191 @@
+package token
+
+import (
+	"unicode/utf8"
+
+	"github.com/a-skua/json-parser/token/internal/runes"
+	"github.com/a-skua/json-parser/token/internal/state"
+)
+
+// ByteToken is a Token whose Value references the input of a ByteTokenizer.
+type ByteToken struct {
+	Type  Type
+	Value []byte
+	Start Position
+	End   Position
+}
+
+// Token copies t into a Token.
+func (t ByteToken) Token() Token {
+	return Token{Type: t.Type, Value: string(t.Value), Start: t.Start, End: t.End}
+}
+
+// ByteTokenizer tokenizes UTF-8 input without converting it to runes.
+type ByteTokenizer struct {
+	data []byte
+	pos  Position
+	opts options
+}
+
+func NewByteTokenizer(data []byte, opts ...Option) ByteTokenizer {
+	return ByteTokenizer{data: data, pos: startPosition(), opts: newOptions(opts)}
+}
+
+// Pos returns the position of the next token.
+func (t *ByteTokenizer) Pos() Position {
+	return t.pos
+}
+
+func (t *ByteTokenizer) Next() (ByteToken, error) {
+	if len(t.data) == 0 {
+		return ByteToken{}, ErrEOT
+	}
+
+	typ, n, err := tokenizeBytes(t.data, t.opts)
+	if err, ok := err.(*SyntaxError); ok {
+		err.locateBytes(t.pos, t.data)
+		return ByteToken{}, err
+	}
+	if err != nil {
+		return ByteToken{}, err
+	}
+
+	token := ByteToken{Type: typ, Value: t.data[:n:n], Start: t.pos}
+	t.pos = t.pos.advanceBytes(t.data[:n])
+	token.End = t.pos
+	t.data = t.data[n:]
+	return token, nil
+}
+
+func TokenizeBytes(data []byte, opts ...Option) ([]ByteToken, error) {
+	tokenizer := NewByteTokenizer(data, opts...)
+	tokens := []ByteToken{}
+	for {
+		t, err := tokenizer.Next()
+		if err == ErrEOT {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+func tokenizeBytes(data []byte, opts options) (Type, int, error) {
+	switch r := rune(data[0]); {
+	case runes.IsWhitespace(r):
+		n := 1
+		for n < len(data) && runes.IsWhitespace(rune(data[n])) {
+			n++
+		}
+		return Whitespace, n, nil
+
+	case hasLiteral(data, "true"):
+		return tokenizeLiteralBytes(True, "true", data)
+
+	case hasLiteral(data, "false"):
+		return tokenizeLiteralBytes(False, "false", data)
+
+	case hasLiteral(data, "null"):
+		return tokenizeLiteralBytes(Null, "null", data)
+
+	case 2 <= len(data) && r == '"':
+		return tokenizeStringBytes(data, opts)
+
+	case runes.MaybeNumber(r):
+		return tokenizeNumberBytes(data)
+
+	case runes.IsLeftBrace(r):
+		return LeftBrace, 1, nil
+
+	case runes.IsRightBrace(r):
+		return RightBrace, 1, nil
+
+	case runes.IsColon(r):
+		return Colon, 1, nil
+
+	case runes.IsComma(r):
+		return Comma, 1, nil
+
+	case runes.IsLeftBracket(r):
+		return LeftBracket, 1, nil
+
+	case runes.IsRightBracket(r):
+		return RightBracket, 1, nil
+
+	default:
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size <= 1 {
+			return 0, 0, newByteSyntaxError(InvalidUTF8, 0, data[:1], 0, nil)
+		}
+		return 0, 0, newByteSyntaxError(UnexpectedCharacter, 0, data[:size], 0, nil)
+	}
+}
+
+func hasLiteral(data []byte, literal string) bool {
+	return len(literal) <= len(data) && string(data[:len(literal)]) == literal
+}
+
+func tokenizeLiteralBytes(t Type, literal string, data []byte) (Type, int, error) {
+	n := len(literal)
+	if l := len(data); n < l &&
+		!runes.IsWhitespace(rune(data[n])) &&
+		!runes.IsComma(rune(data[n])) &&
+		!runes.IsRightBracket(rune(data[n])) &&
+		!runes.IsRightBrace(rune(data[n])) {
+		_, size := utf8.DecodeRune(data[n:])
+		return 0, 0, newByteSyntaxError(InvalidLiteral, t, data[:n+size], n, nil)
+	}
+	return t, n, nil
+}
+
+func tokenizeNumberBytes(data []byte) (Type, int, error) {
+	state := state.NewNumber()
+	n := 0
+	for n < len(data) {
+		r, size := utf8.DecodeRune(data[n:])
+		var err error
+		state, err = state.Next(r)
+		if err != nil {
+			return 0, 0, newByteSyntaxError(InvalidNumber, Number, data[:n+size], n, err)
+		}
+		if state.IsEnd() {
+			break
+		}
+		n += size
+	}
+	if !state.Valid() {
+		return 0, 0, newByteSyntaxError(InvalidNumber, Number, data[:n], n, nil)
+	}
+	return Number, n, nil
+}
+
+func tokenizeStringBytes(data []byte, opts options) (Type, int, error) {
+	strict := state.NewStrict()
+	state := state.NewString()
+	n := 0
+	for n < len(data) {
+		r, size := utf8.DecodeRune(data[n:])
+		if r == utf8.RuneError && size == 1 {
+			return 0, 0, newByteSyntaxError(InvalidUTF8, String, data[:n+size], n, nil)
+		}
+		var err error
+		state, err = state.Next(r)
+		if err == nil && !opts.lenient {
+			err = strict.Next(state, r)
+		}
+		if err != nil {
+			return 0, 0, newByteSyntaxError(InvalidString, String, data[:n+size], n, err)
+		}
+		if state.IsEnd() {
+			break
+		}
+		n += size
+	}
+	if !state.Valid() {
+		return 0, 0, newByteSyntaxError(InvalidString, String, data[:n], n, nil)
+	}
+	return String, n, nil
+}
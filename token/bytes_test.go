@@ -0,0 +1,85 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokenizeBytes(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"empty": {
+			input: "",
+		},
+		"object": {
+			input: "{\n  \"key\": \"Hello, 世界!\",\n  \"array\": [true, false, null, -0.5e+10, \"\\u3042\"]\n}",
+		},
+		"invalid literal": {
+			input:   "[nullx]",
+			wantErr: "1:6: Invalid literal: 'nullx'",
+		},
+		"invalid number": {
+			input:   "[世界, 0.a]",
+			wantErr: "1:2: Unexpected character: '世'",
+		},
+		"invalid number after multibyte string": {
+			input:   `["世界", 0.a]`,
+			wantErr: "1:10: Invalid number: '0.a' (Expected digit after '.': 'a')",
+		},
+		"invalid string": {
+			input:   `"\a"`,
+			wantErr: "1:3: Invalid string: '\"\\a' (Unexpected Escape String: 'a')",
+		},
+		"control character in string": {
+			input:   "[\"\x01\"]",
+			wantErr: "1:3: Invalid string: '\"\x01' (Unexpected control character: U+0001)",
+		},
+		"invalid UTF-8 in string": {
+			input:   "\"a\xffb\"",
+			wantErr: "1:3: Invalid UTF-8: '\"a\xff'",
+		},
+		"invalid UTF-8 (surrogate)": {
+			input:   "[\"\xed\xa0\x80\"]",
+			wantErr: "1:3: Invalid UTF-8: '\"\xed'",
+		},
+		"invalid UTF-8 outside string": {
+			input:   "[\xff]",
+			wantErr: "1:2: Invalid UTF-8: '\xff'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			input := []byte(tt.input)
+			tokens, err := TokenizeBytes(input)
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("TokenizeBytes(%s) error: %v (want: %v)", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("TokenizeBytes(%s) error: nil (want: %v)", tt.input, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			want, err := Tokenize([]rune(tt.input))
+			if err != nil {
+				t.Fatalf("Tokenize(%s) error: %v", tt.input, err)
+			}
+
+			got := make([]Token, len(tokens))
+			for i, token := range tokens {
+				got[i] = token.Token()
+				if &token.Value[0] != &input[token.Start.Offset] {
+					t.Fatalf("TokenizeBytes(%s)[%d] does not reference the input", tt.input, i)
+				}
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("TokenizeBytes(%s) mismatch (-want +got):\n%s", tt.input, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package token
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ErrorKind uint8
+
+const (
+	_ ErrorKind = iota
+	UnexpectedCharacter
+	InvalidLiteral
+	InvalidNumber
+	InvalidString
+	UnexpectedToken
+	UnexpectedEnd
+	InvalidUTF8
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case UnexpectedCharacter:
+		return "Unexpected character"
+	case InvalidLiteral:
+		return "Invalid literal"
+	case InvalidNumber:
+		return "Invalid number"
+	case InvalidString:
+		return "Invalid string"
+	case UnexpectedToken:
+		return "Unexpected token"
+	case UnexpectedEnd:
+		return "Unexpected end of input"
+	case InvalidUTF8:
+		return "Invalid UTF-8"
+	default:
+		return fmt.Sprintf("ErrorKind(%d)", k)
+	}
+}
+
+// SyntaxError describes malformed input.
+// Token holds the offending text, Pos the position where the error was detected.
+type SyntaxError struct {
+	Kind     ErrorKind
+	Pos      Position
+	Token    Token
+	Expected []Type
+	Err      error
+
+	// offset of the error from the start of Token,
+	// in runes for Tokenizer and in bytes for ByteTokenizer.
+	at int
+}
+
+func (e *SyntaxError) Error() string {
+	str := fmt.Sprintf("%s: %s", e.Pos, e.Kind)
+	if e.Kind != UnexpectedEnd || e.Token.Value != "" {
+		str += fmt.Sprintf(": '%s'", e.Token.Value)
+	}
+	if len(e.Expected) > 0 {
+		str += ", expected " + sprintTypes(e.Expected)
+	}
+	if e.Err != nil {
+		str += fmt.Sprintf(" (%v)", e.Err)
+	}
+	return str
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// locate resolves the position of the error from the start position of the token.
+func (e *SyntaxError) locate(start Position, data []rune) {
+	e.Token.Start = start
+	e.Token.End = start.advance(data[:len([]rune(e.Token.Value))])
+	e.Pos = start.advance(data[:e.at])
+}
+
+// locateBytes is locate for ByteTokenizer.
+func (e *SyntaxError) locateBytes(start Position, data []byte) {
+	e.Token.Start = start
+	e.Token.End = start.advanceBytes(data[:len(e.Token.Value)])
+	e.Pos = start.advanceBytes(data[:e.at])
+}
+
+func newSyntaxError(kind ErrorKind, t Type, data []rune, at int, err error) *SyntaxError {
+	return &SyntaxError{
+		Kind:  kind,
+		Token: Token{Type: t, Value: runesString(data)},
+		Err:   err,
+		at:    at,
+	}
+}
+
+func newByteSyntaxError(kind ErrorKind, t Type, data []byte, at int, err error) *SyntaxError {
+	return &SyntaxError{
+		Kind:  kind,
+		Token: Token{Type: t, Value: string(data)},
+		Err:   err,
+		at:    at,
+	}
+}
+
+func sprintTypes(types []Type) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.String()
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
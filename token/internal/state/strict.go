@@ -0,0 +1,70 @@
+package state
+
+import (
+	"fmt"
+	"unicode/utf16"
+
+	"github.com/a-skua/json-parser/token/internal/runes"
+)
+
+// Strict rejects strings String accepts but RFC 8259 forbids:
+// unescaped control characters and unpaired surrogates.
+type Strict struct {
+	unit rune
+	high bool
+}
+
+func NewStrict() Strict {
+	return Strict{}
+}
+
+// Next validates r, given s is the state of String after r.
+func (v *Strict) Next(s String, r rune) error {
+	switch s {
+	case StringCodepoint:
+		if r < 0x20 {
+			return fmt.Errorf("Unexpected control character: %U", r)
+		}
+	case StringEscapeSymbol:
+		return nil
+	case StringHexDigitSymbol:
+		v.unit = 0
+		return nil
+	case StringHexDigit1, StringHexDigit2, StringHexDigit3:
+		v.unit = v.unit<<4 | hexValue(r)
+		return nil
+	case StringHexDigit4:
+		v.unit = v.unit<<4 | hexValue(r)
+		return v.surrogateNext()
+	}
+
+	if v.high {
+		return fmt.Errorf("Expected low surrogate: '%c'", r)
+	}
+	return nil
+}
+
+// 0xD800-0xDBFF => 0xDC00-0xDFFF
+func (v *Strict) surrogateNext() error {
+	isHigh := utf16.IsSurrogate(v.unit) && v.unit < 0xDC00
+	isLow := utf16.IsSurrogate(v.unit) && !isHigh
+	switch {
+	case v.high && !isLow:
+		return fmt.Errorf("Expected low surrogate: \\u%04X", v.unit)
+	case !v.high && isLow:
+		return fmt.Errorf("Unexpected low surrogate: \\u%04X", v.unit)
+	}
+	v.high = isHigh
+	return nil
+}
+
+func hexValue(r rune) rune {
+	switch {
+	case runes.IsDigit(r):
+		return r - '0'
+	case 'a' <= r && r <= 'f':
+		return r - 'a' + 10
+	default:
+		return r - 'A' + 10
+	}
+}
@@ -0,0 +1,82 @@
+package state
+
+import (
+	"testing"
+)
+
+func TestStrict_Next(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"codepoints": {
+			input: `"Hello, 世界!"`,
+		},
+		"escapes": {
+			input: `"\"\\\/\b\f\n\r\t\u0000\u001F"`,
+		},
+		"surrogate pair": {
+			input: `"\uD83D\uDE00"`,
+		},
+		"surrogate pair (lower case)": {
+			input: `"\ud83d\ude00"`,
+		},
+		"control character": {
+			input:   "\"a\nb\"",
+			wantErr: "Unexpected control character: U+000A",
+		},
+		"control character after escape": {
+			input:   "\"\\n\x00\"",
+			wantErr: "Unexpected control character: U+0000",
+		},
+		"lone high surrogate": {
+			input:   `"\uD83Da"`,
+			wantErr: "Expected low surrogate: 'a'",
+		},
+		"lone high surrogate at the end": {
+			input:   `"\uD83D"`,
+			wantErr: "Expected low surrogate: '\"'",
+		},
+		"high surrogate followed by escape": {
+			input:   `"\uD83D\n"`,
+			wantErr: "Expected low surrogate: 'n'",
+		},
+		"high surrogate followed by high surrogate": {
+			input:   `"\uD83D\uD83D"`,
+			wantErr: "Expected low surrogate: \\uD83D",
+		},
+		"high surrogate followed by non surrogate": {
+			input:   `"\uD83D\u0041"`,
+			wantErr: "Expected low surrogate: \\u0041",
+		},
+		"lone low surrogate": {
+			input:   `"\uDE00"`,
+			wantErr: "Unexpected low surrogate: \\uDE00",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			strict := NewStrict()
+			state := NewString()
+			for _, r := range tt.input {
+				state, err = state.Next(r)
+				if err != nil {
+					t.Fatalf("String.Next(%c) error: %v", r, err)
+				}
+				err = strict.Next(state, r)
+				if err != nil {
+					break
+				}
+			}
+
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Strict.Next(%s) error: %v (want: %v)", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tt.wantErr != "" {
+				t.Fatalf("Strict.Next(%s) error: nil (want: %v)", tt.input, tt.wantErr)
+			}
+		})
+	}
+}
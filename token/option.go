@@ -0,0 +1,23 @@
+package token
+
+type Option func(*options)
+
+type options struct {
+	lenient bool
+}
+
+// Lenient accepts unescaped control characters and unpaired surrogates in strings,
+// which are rejected by default.
+func Lenient() Option {
+	return func(o *options) {
+		o.lenient = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
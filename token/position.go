@@ -0,0 +1,65 @@
+package token
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Position is a location in the input.
+// Offset and RuneOffset are 0-based, Line and Column are 1-based.
+// Column counts runes.
+type Position struct {
+	Offset     int
+	RuneOffset int
+	Line       int
+	Column     int
+}
+
+func startPosition() Position {
+	return Position{Line: 1, Column: 1}
+}
+
+func (p Position) advance(data []rune) Position {
+	for _, r := range data {
+		p.Offset += runeWidth(r)
+		p.RuneOffset++
+		if r == '\n' {
+			p.Line++
+			p.Column = 1
+		} else {
+			p.Column++
+		}
+	}
+	return p
+}
+
+// runeWidth returns the number of bytes r was read from.
+func runeWidth(r rune) int {
+	if isInvalidByte(r) {
+		return 1
+	}
+	if n := utf8.RuneLen(r); n > 0 {
+		return n
+	}
+	return utf8.RuneLen(utf8.RuneError)
+}
+
+func (p Position) advanceBytes(data []byte) Position {
+	for len(data) > 0 {
+		r, n := utf8.DecodeRune(data)
+		data = data[n:]
+		p.Offset += n
+		p.RuneOffset++
+		if r == '\n' {
+			p.Line++
+			p.Column = 1
+		} else {
+			p.Column++
+		}
+	}
+	return p
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
@@ -0,0 +1,77 @@
+package token
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+)
+
+// DefaultBufferSize is the number of runes read at a time by NewReaderTokenizer.
+const DefaultBufferSize = 4096
+
+// lookahead is the number of runes required to choose a tokenizer (see runes.MaybeFalse).
+const lookahead = 5
+
+func NewReaderTokenizer(r io.Reader, opts ...Option) Tokenizer {
+	return NewReaderTokenizerSize(r, DefaultBufferSize, opts...)
+}
+
+// NewReaderTokenizerSize returns a Tokenizer reading from r, buffering at least size runes.
+// Tokens longer than the buffer grow it as needed.
+func NewReaderTokenizerSize(r io.Reader, size int, opts ...Option) Tokenizer {
+	if size < lookahead {
+		size = lookahead
+	}
+	return Tokenizer{
+		pos:    startPosition(),
+		reader: bufio.NewReader(r),
+		size:   size,
+		opts:   newOptions(opts),
+	}
+}
+
+// fill reads up to n more runes from the reader, discarding consumed runes.
+func (t *Tokenizer) fill(n int) error {
+	if t.reader == nil || t.eof {
+		return nil
+	}
+
+	data := append(t.buf[:0], t.data...)
+	for i := 0; i < max(n, t.size); i++ {
+		r, err := t.readRune()
+		if err == io.EOF {
+			t.eof = true
+			break
+		}
+		if err != nil {
+			return err
+		}
+		data = append(data, r)
+	}
+
+	t.buf = data
+	t.data = data
+	return nil
+}
+
+// readRune reads a rune as Runes converts it.
+func (t *Tokenizer) readRune() (rune, error) {
+	r, size, err := t.reader.ReadRune()
+	if err != nil || r != utf8.RuneError || size != 1 {
+		return r, err
+	}
+	t.reader.UnreadRune()
+	b, err := t.reader.ReadByte()
+	return invalidByte(b), err
+}
+
+// incomplete reports whether the result of tokenize may change with more input.
+func (t *Tokenizer) incomplete(n int, err error) bool {
+	if t.reader == nil || t.eof {
+		return false
+	}
+	if err, ok := err.(*SyntaxError); ok {
+		return len(t.data) <= err.at
+	}
+	return err == nil && n == len(t.data)
+}
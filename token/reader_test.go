@@ -0,0 +1,85 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewReaderTokenizerSize(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"literals": {
+			input: "[true, false, null]",
+		},
+		"literal at the end of input": {
+			input: "true",
+		},
+		"string across buffers": {
+			input: `{"key": "Hello, 世界! あ\n", "long": "` + strings.Repeat("a", 100) + `"}`,
+		},
+		"number across buffers": {
+			input: "[123456789, -0.125e+10, 0]",
+		},
+		"whitespace across buffers": {
+			input: "[" + strings.Repeat(" \r\n\t", 10) + "]",
+		},
+		"invalid UTF-8": {
+			input:   "[\"a\xffb\", 1]",
+			wantErr: "1:4: Invalid UTF-8: '\"a\xff'",
+		},
+		"truncated UTF-8 at the end of input": {
+			input:   "\"\xe4\xb8",
+			wantErr: "1:2: Invalid UTF-8: '\"\xe4'",
+		},
+		"invalid literal": {
+			input:   "[truex]",
+			wantErr: "1:6: Invalid literal: 'truex'",
+		},
+		"invalid number at the end of input": {
+			input:   "[1, 0.",
+			wantErr: "1:7: Invalid number: '0.'",
+		},
+		"unterminated string": {
+			input:   `"abcdefghij`,
+			wantErr: "1:12: Invalid string: '\"abcdefghij'",
+		},
+	}
+
+	for name, tt := range tests {
+		for _, size := range []int{1, 2, 7, DefaultBufferSize} {
+			t.Run(name, func(t *testing.T) {
+				want, _ := Tokenize(Runes(tt.input))
+
+				tokenizer := NewReaderTokenizerSize(strings.NewReader(tt.input), size)
+				got := []Token{}
+				var err error
+				for {
+					var token Token
+					token, err = tokenizer.Next()
+					if err != nil {
+						break
+					}
+					got = append(got, token)
+				}
+
+				if err == ErrEOT && tt.wantErr != "" {
+					t.Fatalf("Next() (size: %d) error: nil (want: %v)", size, tt.wantErr)
+				}
+				if err != ErrEOT && err.Error() != tt.wantErr {
+					t.Fatalf("Next() (size: %d) error: %v (want: %v)", size, err, tt.wantErr)
+				}
+				if err != ErrEOT {
+					return
+				}
+
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Fatalf("Next() (size: %d) mismatch (-want +got):\n%s", size, diff)
+				}
+			})
+		}
+	}
+}
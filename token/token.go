@@ -1,8 +1,9 @@
 package token
 
 import (
+	"bufio"
 	"errors"
-	"fmt"
+	"unicode/utf8"
 
 	"github.com/a-skua/json-parser/token/internal/runes"
 	"github.com/a-skua/json-parser/token/internal/state"
@@ -30,6 +31,8 @@ const (
 type Token struct {
 	Type  Type
 	Value string
+	Start Position
+	End   Position
 }
 
 func New(t Type, runes []rune) Token {
@@ -38,102 +41,156 @@ func New(t Type, runes []rune) Token {
 
 type Tokenizer struct {
 	data []rune
+	pos  Position
+	opts options
+
+	// reader is set when the tokenizer streams its input.
+	reader *bufio.Reader
+	buf    []rune
+	size   int
+	eof    bool
 }
 
-func NewTokenizer(data []rune) Tokenizer {
-	return Tokenizer{data: data}
+func NewTokenizer(data []rune, opts ...Option) Tokenizer {
+	return Tokenizer{data: data, pos: startPosition(), opts: newOptions(opts)}
+}
+
+// Runes converts s to runes for NewTokenizer. Unlike []rune(s), it keeps
+// each byte of invalid UTF-8 as a rune of its own, so that positions count
+// it as one byte.
+func Runes(s string) []rune {
+	data := make([]rune, 0, len(s))
+	for len(s) > 0 {
+		r, n := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError && n == 1 {
+			r = invalidByte(s[0])
+		}
+		data = append(data, r)
+		s = s[n:]
+	}
+	return data
+}
+
+// invalidBytes holds the bytes of invalid UTF-8 in runes 0xDC80 to 0xDCFF,
+// low surrogates that decoding UTF-8 never returns.
+const invalidBytes rune = 0xDC00
+
+func invalidByte(b byte) rune {
+	return invalidBytes + rune(b)
+}
+
+func isInvalidByte(r rune) bool {
+	return invalidBytes+0x80 <= r && r <= invalidBytes+0xFF
+}
+
+// runesString is string(data) with invalid bytes written as they were read.
+func runesString(data []rune) string {
+	b := make([]byte, 0, len(data))
+	for _, r := range data {
+		if isInvalidByte(r) {
+			b = append(b, byte(r-invalidBytes))
+		} else {
+			b = utf8.AppendRune(b, r)
+		}
+	}
+	return string(b)
 }
 
 var ErrEOT = errors.New("End of Token")
 
+// Pos returns the position of the next token.
+func (t *Tokenizer) Pos() Position {
+	return t.pos
+}
+
 func (t *Tokenizer) Next() (Token, error) {
+	if len(t.data) < lookahead {
+		if err := t.fill(t.size); err != nil {
+			return Token{}, err
+		}
+	}
+
 	if len(t.data) == 0 {
 		return Token{}, ErrEOT
 	}
 
-	switch {
-	case runes.IsWhitespace(t.data[0]):
-		token, n := tokenizeWhitespace(t.data)
-		t.data = t.data[n:]
-		return token, nil
-
-	case runes.MaybeTrue(t.data):
-		token, n, err := tokenizeTrue(t.data)
-		if err != nil {
+	token, n, err := tokenize(t.data, t.opts)
+	for t.incomplete(n, err) {
+		if err := t.fill(len(t.data)); err != nil {
 			return Token{}, err
 		}
-		t.data = t.data[n:]
-		return token, nil
+		token, n, err = tokenize(t.data, t.opts)
+	}
+	if err, ok := err.(*SyntaxError); ok {
+		err.locate(t.pos, t.data)
+		return Token{}, err
+	}
+	if err != nil {
+		return Token{}, err
+	}
 
-	case runes.MaybeFalse(t.data):
-		token, n, err := tokenizeFalse(t.data)
-		if err != nil {
-			return Token{}, err
-		}
-		t.data = t.data[n:]
-		return token, nil
+	token.Start = t.pos
+	t.pos = t.pos.advance(t.data[:n])
+	token.End = t.pos
+	t.data = t.data[n:]
+	return token, nil
+}
 
-	case runes.MaybeNull(t.data):
-		token, n, err := tokenizeNull(t.data)
-		if err != nil {
-			return Token{}, err
-		}
-		t.data = t.data[n:]
-		return token, nil
+func tokenize(data []rune, opts options) (Token, int, error) {
+	switch {
+	case runes.IsWhitespace(data[0]):
+		token, n := tokenizeWhitespace(data)
+		return token, n, nil
 
-	case runes.MaybeString(t.data):
-		token, n, err := tokenizeString(t.data)
-		if err != nil {
-			return Token{}, err
-		}
-		t.data = t.data[n:]
-		return token, nil
+	case runes.MaybeTrue(data):
+		return tokenizeTrue(data)
 
-	case runes.MaybeNumber(t.data[0]):
-		token, n, err := tokenizeNumber(t.data)
-		if err != nil {
-			return Token{}, err
-		}
-		t.data = t.data[n:]
-		return token, nil
-
-	case runes.IsLeftBrace(t.data[0]):
-		token, n := tokenizeLeftBrace(t.data)
-		t.data = t.data[n:]
-		return token, nil
-
-	case runes.IsRightBrace(t.data[0]):
-		token, n := tokenizeRightBrace(t.data)
-		t.data = t.data[n:]
-		return token, nil
-
-	case runes.IsColon(t.data[0]):
-		token, n := tokenizeColon(t.data)
-		t.data = t.data[n:]
-		return token, nil
-
-	case runes.IsComma(t.data[0]):
-		token, n := tokenizeComma(t.data)
-		t.data = t.data[n:]
-		return token, nil
-
-	case runes.IsLeftBracket(t.data[0]):
-		token, n := tokenizeLeftBracket(t.data)
-		t.data = t.data[n:]
-		return token, nil
-
-	case runes.IsRightBracket(t.data[0]):
-		token, n := tokenizeRightBracket(t.data)
-		t.data = t.data[n:]
-		return token, nil
+	case runes.MaybeFalse(data):
+		return tokenizeFalse(data)
+
+	case runes.MaybeNull(data):
+		return tokenizeNull(data)
+
+	case runes.MaybeString(data):
+		return tokenizeString(data, opts)
+
+	case runes.MaybeNumber(data[0]):
+		return tokenizeNumber(data)
+
+	case runes.IsLeftBrace(data[0]):
+		token, n := tokenizeLeftBrace(data)
+		return token, n, nil
+
+	case runes.IsRightBrace(data[0]):
+		token, n := tokenizeRightBrace(data)
+		return token, n, nil
+
+	case runes.IsColon(data[0]):
+		token, n := tokenizeColon(data)
+		return token, n, nil
+
+	case runes.IsComma(data[0]):
+		token, n := tokenizeComma(data)
+		return token, n, nil
+
+	case runes.IsLeftBracket(data[0]):
+		token, n := tokenizeLeftBracket(data)
+		return token, n, nil
+
+	case runes.IsRightBracket(data[0]):
+		token, n := tokenizeRightBracket(data)
+		return token, n, nil
+
+	case isInvalidByte(data[0]):
+		return Token{}, 0, newSyntaxError(InvalidUTF8, 0, data[:1], 0, nil)
 
 	default:
-		return Token{}, fmt.Errorf("Unexpected token: '%s'", string(t.data[0]))
+		return Token{}, 0, newSyntaxError(UnexpectedCharacter, 0, data[:1], 0, nil)
 	}
 }
 
-func Tokenize(data []rune) ([]Token, error) {
-	tokenizer := NewTokenizer(data)
+func Tokenize(data []rune, opts ...Option) ([]Token, error) {
+	tokenizer := NewTokenizer(data, opts...)
 	tokens := []Token{}
 
 	for {
@@ -187,53 +244,32 @@ func tokenizeWhitespace(data []rune) (Token, int) {
 }
 
 func tokenizeTrue(data []rune) (Token, int, error) {
-	if l := len(data); (l < 4) ||
-		(l == 4 && string(data) != "true") {
-		return Token{}, 0, fmt.Errorf("Unexpected Token: '%s'", string(data))
-	}
-	if l := len(data); 4 < l &&
-		!runes.IsWhitespace(data[4]) &&
-		!runes.IsComma(data[4]) &&
-		!runes.IsRightBracket(data[4]) &&
-		!runes.IsRightBrace(data[4]) {
-		return Token{}, 0, fmt.Errorf("Unexpected Token: true'%s'", string(data[4]))
-	}
-
-	return New(True, data[:4]), 4, nil
+	return tokenizeLiteral(True, "true", data)
 }
 
 func tokenizeFalse(data []rune) (Token, int, error) {
-	if l := len(data); (l < 5) ||
-		(l == 5 && string(data) != "false") {
-		return Token{}, 0, fmt.Errorf("Unexpected Token:'%s'", string(data))
-	}
-
-	if l := len(data); 5 < l &&
-		!runes.IsWhitespace(data[5]) &&
-		!runes.IsComma(data[5]) &&
-		!runes.IsRightBracket(data[5]) &&
-		!runes.IsRightBrace(data[5]) {
-		return Token{}, 0, fmt.Errorf("Unexpected Token: false'%s'", string(data[5]))
-	}
-
-	return New(False, data[:5]), 5, nil
+	return tokenizeLiteral(False, "false", data)
 }
 
 func tokenizeNull(data []rune) (Token, int, error) {
-	if l := len(data); (l < 4) ||
-		(l == 4 && string(data) != "null") {
-		return Token{}, 0, fmt.Errorf("Unexpected Token: '%s'", string(data))
+	return tokenizeLiteral(Null, "null", data)
+}
+
+func tokenizeLiteral(t Type, literal string, data []rune) (Token, int, error) {
+	n := len([]rune(literal))
+	if l := len(data); l < n || string(data[:n]) != literal {
+		return Token{}, 0, newSyntaxError(InvalidLiteral, t, data[:min(l, n)], 0, nil)
 	}
 
-	if l := len(data); 4 < l &&
-		!runes.IsWhitespace(data[4]) &&
-		!runes.IsComma(data[4]) &&
-		!runes.IsRightBracket(data[4]) &&
-		!runes.IsRightBrace(data[4]) {
-		return Token{}, 0, fmt.Errorf("Unexpected Token: null'%s'", string(data[4]))
+	if l := len(data); n < l &&
+		!runes.IsWhitespace(data[n]) &&
+		!runes.IsComma(data[n]) &&
+		!runes.IsRightBracket(data[n]) &&
+		!runes.IsRightBrace(data[n]) {
+		return Token{}, 0, newSyntaxError(InvalidLiteral, t, data[:n+1], n, nil)
 	}
 
-	return New(Null, data[:4]), 4, nil
+	return New(t, data[:n]), n, nil
 }
 
 func tokenizeNumber(data []rune) (Token, int, error) {
@@ -244,7 +280,7 @@ func tokenizeNumber(data []rune) (Token, int, error) {
 		var err error
 		state, err = state.Next(r)
 		if err != nil {
-			return Token{}, 0, fmt.Errorf("Invalid number: %s'%s' (%w)", string(number), string(r), err)
+			return Token{}, 0, newSyntaxError(InvalidNumber, Number, data[:len(number)+1], len(number), err)
 		}
 		if state.IsEnd() {
 			break
@@ -253,21 +289,28 @@ func tokenizeNumber(data []rune) (Token, int, error) {
 	}
 
 	if !state.Valid() {
-		return Token{}, 0, fmt.Errorf("Invalid number: '%s'", string(number))
+		return Token{}, 0, newSyntaxError(InvalidNumber, Number, number, len(number), nil)
 	}
 
 	return New(Number, number), len(number), nil
 }
 
-func tokenizeString(data []rune) (Token, int, error) {
+func tokenizeString(data []rune, opts options) (Token, int, error) {
+	strict := state.NewStrict()
 	state := state.NewString()
 	strings := []rune{}
 
 	for _, r := range data {
+		if isInvalidByte(r) {
+			return Token{}, 0, newSyntaxError(InvalidUTF8, String, data[:len(strings)+1], len(strings), nil)
+		}
 		var err error
 		state, err = state.Next(r)
+		if err == nil && !opts.lenient {
+			err = strict.Next(state, r)
+		}
 		if err != nil {
-			return Token{}, 0, fmt.Errorf("Invalid string: %s'%s' (%w)", string(strings), string(r), err)
+			return Token{}, 0, newSyntaxError(InvalidString, String, data[:len(strings)+1], len(strings), err)
 		}
 		if state.IsEnd() {
 			break
@@ -276,7 +319,7 @@ func tokenizeString(data []rune) (Token, int, error) {
 	}
 
 	if !state.Valid() {
-		return Token{}, 0, fmt.Errorf("Invalid string: '%s'", string(strings))
+		return Token{}, 0, newSyntaxError(InvalidString, String, strings, len(strings), nil)
 	}
 
 	return New(String, strings), len(strings), nil
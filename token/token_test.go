@@ -1,9 +1,11 @@
 package token
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestTokenize(t *testing.T) {
@@ -19,234 +21,242 @@ func TestTokenize(t *testing.T) {
 		"whitespace: space": {
 			input: " ",
 			want: []Token{
-				{Whitespace, " "},
+				{Type: Whitespace, Value: " "},
 			},
 		},
 		"whitespace: linefeed": {
 			input: "\n",
 			want: []Token{
-				{Whitespace, "\n"},
+				{Type: Whitespace, Value: "\n"},
 			},
 		},
 		"whitespace: carriage return": {
 			input: "\r",
 			want: []Token{
-				{Whitespace, "\r"},
+				{Type: Whitespace, Value: "\r"},
 			},
 		},
 		"whitespace: horizontal tab": {
 			input: "\t",
 			want: []Token{
-				{Whitespace, "\t"},
+				{Type: Whitespace, Value: "\t"},
 			},
 		},
 		"whitespace: multiple": {
 			input: "    \r\n\t",
 			want: []Token{
-				{Whitespace, "    \r\n\t"},
+				{Type: Whitespace, Value: "    \r\n\t"},
 			},
 		},
 		"true: ok": {
 			input: "true,",
 			want: []Token{
-				{True, "true"},
-				{Comma, ","},
+				{Type: True, Value: "true"},
+				{Type: Comma, Value: ","},
 			},
 		},
 		"false: ok": {
 			input: "false,",
 			want: []Token{
-				{False, "false"},
-				{Comma, ","},
+				{Type: False, Value: "false"},
+				{Type: Comma, Value: ","},
 			},
 		},
 		"null: ok": {
 			input: "null,",
 			want: []Token{
-				{Null, "null"},
-				{Comma, ","},
+				{Type: Null, Value: "null"},
+				{Type: Comma, Value: ","},
 			},
 		},
 		"string: ok": {
 			input: `"Hello, 世界!"`,
 			want: []Token{
-				{String, `"Hello, 世界!"`},
+				{Type: String, Value: `"Hello, 世界!"`},
 			},
 		},
 		"string: emplyt": {
 			input: `""`,
 			want: []Token{
-				{String, `""`},
+				{Type: String, Value: `""`},
 			},
 		},
 		"string: escape quotation mark (\\\")": {
 			input: `"\""`,
 			want: []Token{
-				{String, `"\""`},
+				{Type: String, Value: `"\""`},
 			},
 		},
 		"string: escape reverse solidus (\\\\)": {
 			input: `"\\"`,
 			want: []Token{
-				{String, `"\\"`},
+				{Type: String, Value: `"\\"`},
 			},
 		},
 		"string: escape solidus (\\/)": {
 			input: `"\/"`,
 			want: []Token{
-				{String, `"\/"`},
+				{Type: String, Value: `"\/"`},
 			},
 		},
 		"string: escape backspace (\\b)": {
 			input: `"\b"`,
 			want: []Token{
-				{String, `"\b"`},
+				{Type: String, Value: `"\b"`},
 			},
 		},
 		"string: escape formfeed (\\f)": {
 			input: `"\f"`,
 			want: []Token{
-				{String, `"\f"`},
+				{Type: String, Value: `"\f"`},
 			},
 		},
 		"string: escape linefeed (\\n)": {
 			input: `"\n"`,
 			want: []Token{
-				{String, `"\n"`},
+				{Type: String, Value: `"\n"`},
 			},
 		},
 		"string: escape carriage return (\\r)": {
 			input: `"\r"`,
 			want: []Token{
-				{String, `"\r"`},
+				{Type: String, Value: `"\r"`},
 			},
 		},
 		"string: escape horizontal tab (\\t)": {
 			input: `"\t"`,
 			want: []Token{
-				{String, `"\t"`},
+				{Type: String, Value: `"\t"`},
 			},
 		},
 		"string: escape unicode (\\uXXXX)": {
 			input: `"\u3042"`,
 			want: []Token{
-				{String, `"\u3042"`},
+				{Type: String, Value: `"\u3042"`},
 			},
 		},
 		"string: ng invalid escape": {
 			input:   `"\a"`,
-			wantErr: "Invalid string: \"\\'a' (Unexpected Escape String: 'a')",
+			wantErr: "1:3: Invalid string: '\"\\a' (Unexpected Escape String: 'a')",
 		},
 		"string: ng invalid unicode (not hex digit)": {
 			input:   `"\u30G2"`,
-			wantErr: "Invalid string: \"\\u30'G' (Unexpected unicode: 'G')",
+			wantErr: "1:6: Invalid string: '\"\\u30G' (Unexpected unicode: 'G')",
+		},
+		"string: ng control character": {
+			input:   "\"a\tb\"",
+			wantErr: "1:3: Invalid string: '\"a\t' (Unexpected control character: U+0009)",
+		},
+		"string: ng lone surrogate": {
+			input:   `"\uD800"`,
+			wantErr: "1:8: Invalid string: '\"\\uD800\"' (Expected low surrogate: '\"')",
 		},
 		"number: 0": {
 			input: "0",
 			want: []Token{
-				{Number, "0"},
+				{Type: Number, Value: "0"},
 			},
 		},
 		"number: -0": {
 			input: "-0",
 			want: []Token{
-				{Number, "-0"},
+				{Type: Number, Value: "-0"},
 			},
 		},
 		"number: 123": {
 			input: "123",
 			want: []Token{
-				{Number, "123"},
+				{Type: Number, Value: "123"},
 			},
 		},
 		"number: -123": {
 			input: "-123",
 			want: []Token{
-				{Number, "-123"},
+				{Type: Number, Value: "-123"},
 			},
 		},
 		"number: 0.0": {
 			input: "0.0",
 			want: []Token{
-				{Number, "0.0"},
+				{Type: Number, Value: "0.0"},
 			},
 		},
 		"number: -0.0": {
 			input: "-0.0",
 			want: []Token{
-				{Number, "-0.0"},
+				{Type: Number, Value: "-0.0"},
 			},
 		},
 		"number: 0e0": {
 			input: "0e0",
 			want: []Token{
-				{Number, "0e0"},
+				{Type: Number, Value: "0e0"},
 			},
 		},
 		"number: 0E0": {
 			input: "0E0",
 			want: []Token{
-				{Number, "0E0"},
+				{Type: Number, Value: "0E0"},
 			},
 		},
 		"number: 0e+0": {
 			input: "0e+0",
 			want: []Token{
-				{Number, "0e+0"},
+				{Type: Number, Value: "0e+0"},
 			},
 		},
 		"number: 0e-0": {
 			input: "0e-0",
 			want: []Token{
-				{Number, "0e-0"},
+				{Type: Number, Value: "0e-0"},
 			},
 		},
 		"number: 0.1": {
 			input: "0.1",
 			want: []Token{
-				{Number, "0.1"},
+				{Type: Number, Value: "0.1"},
 			},
 		},
 		"number: 1e-1": {
 			input: "1e-1",
 			want: []Token{
-				{Number, "1e-1"},
+				{Type: Number, Value: "1e-1"},
 			},
 		},
 		"number: ng 0.a": {
 			input:   "0.a",
-			wantErr: "Invalid number: 0.'a' (Expected digit after '.': 'a')",
+			wantErr: "1:3: Invalid number: '0.a' (Expected digit after '.': 'a')",
 		},
 		"number: ng 0.": {
 			input:   "0.",
-			wantErr: "Invalid number: '0.'",
+			wantErr: "1:3: Invalid number: '0.'",
 		},
 		"object": {
 			input: `{"key": "value", "key2": "value2"}`,
 			want: []Token{
-				{LeftBrace, "{"},
-				{String, `"key"`},
-				{Colon, ":"},
-				{Whitespace, " "},
-				{String, `"value"`},
-				{Comma, ","},
-				{Whitespace, " "},
-				{String, `"key2"`},
-				{Colon, ":"},
-				{Whitespace, " "},
-				{String, `"value2"`},
-				{RightBrace, "}"},
+				{Type: LeftBrace, Value: "{"},
+				{Type: String, Value: `"key"`},
+				{Type: Colon, Value: ":"},
+				{Type: Whitespace, Value: " "},
+				{Type: String, Value: `"value"`},
+				{Type: Comma, Value: ","},
+				{Type: Whitespace, Value: " "},
+				{Type: String, Value: `"key2"`},
+				{Type: Colon, Value: ":"},
+				{Type: Whitespace, Value: " "},
+				{Type: String, Value: `"value2"`},
+				{Type: RightBrace, Value: "}"},
 			},
 		},
 		"array": {
 			input: `["value1", "value2"]`,
 			want: []Token{
-				{LeftBracket, "["},
-				{String, `"value1"`},
-				{Comma, ","},
-				{Whitespace, " "},
-				{String, `"value2"`},
-				{RightBracket, "]"},
+				{Type: LeftBracket, Value: "["},
+				{Type: String, Value: `"value1"`},
+				{Type: Comma, Value: ","},
+				{Type: Whitespace, Value: " "},
+				{Type: String, Value: `"value2"`},
+				{Type: RightBracket, Value: "]"},
 			},
 		},
 	}
@@ -258,9 +268,180 @@ func TestTokenize(t *testing.T) {
 				t.Fatalf("Tokenize(%s) error: %v (want: %v)", tt.input, err, tt.wantErr)
 			}
 
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(Token{}, "Start", "End")); diff != "" {
+				t.Fatalf("Tokenize(%s) mismatch (-want +got):\n%s", tt.input, diff)
+			}
+		})
+	}
+}
+
+func TestTokenizer_Next_position(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  []Token
+	}{
+		"single line": {
+			input: `[1, true]`,
+			want: []Token{
+				{Type: LeftBracket, Value: "[", Start: Position{0, 0, 1, 1}, End: Position{1, 1, 1, 2}},
+				{Type: Number, Value: "1", Start: Position{1, 1, 1, 2}, End: Position{2, 2, 1, 3}},
+				{Type: Comma, Value: ",", Start: Position{2, 2, 1, 3}, End: Position{3, 3, 1, 4}},
+				{Type: Whitespace, Value: " ", Start: Position{3, 3, 1, 4}, End: Position{4, 4, 1, 5}},
+				{Type: True, Value: "true", Start: Position{4, 4, 1, 5}, End: Position{8, 8, 1, 9}},
+				{Type: RightBracket, Value: "]", Start: Position{8, 8, 1, 9}, End: Position{9, 9, 1, 10}},
+			},
+		},
+		"multiple lines": {
+			input: "{\n  \"世界\": null\n}",
+			want: []Token{
+				{Type: LeftBrace, Value: "{", Start: Position{0, 0, 1, 1}, End: Position{1, 1, 1, 2}},
+				{Type: Whitespace, Value: "\n  ", Start: Position{1, 1, 1, 2}, End: Position{4, 4, 2, 3}},
+				{Type: String, Value: `"世界"`, Start: Position{4, 4, 2, 3}, End: Position{12, 8, 2, 7}},
+				{Type: Colon, Value: ":", Start: Position{12, 8, 2, 7}, End: Position{13, 9, 2, 8}},
+				{Type: Whitespace, Value: " ", Start: Position{13, 9, 2, 8}, End: Position{14, 10, 2, 9}},
+				{Type: Null, Value: "null", Start: Position{14, 10, 2, 9}, End: Position{18, 14, 2, 13}},
+				{Type: Whitespace, Value: "\n", Start: Position{18, 14, 2, 13}, End: Position{19, 15, 3, 1}},
+				{Type: RightBrace, Value: "}", Start: Position{19, 15, 3, 1}, End: Position{20, 16, 3, 2}},
+			},
+		},
+		"replacement character": {
+			input: "[\"\uFFFD\"]",
+			want: []Token{
+				{Type: LeftBracket, Value: "[", Start: Position{0, 0, 1, 1}, End: Position{1, 1, 1, 2}},
+				{Type: String, Value: "\"\uFFFD\"", Start: Position{1, 1, 1, 2}, End: Position{6, 4, 1, 5}},
+				{Type: RightBracket, Value: "]", Start: Position{6, 4, 1, 5}, End: Position{7, 5, 1, 6}},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Tokenize(Runes(tt.input))
+			if err != nil {
+				t.Fatalf("Tokenize(%s) error: %v", tt.input, err)
+			}
+
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Fatalf("Tokenize(%s) mismatch (-want +got):\n%s", tt.input, diff)
 			}
 		})
 	}
 }
+
+func TestTokenize_syntaxError(t *testing.T) {
+	tests := map[string]struct {
+		input     string
+		want      *SyntaxError
+		wantState bool
+	}{
+		"unexpected character": {
+			input: "[\n  x]",
+			want: &SyntaxError{
+				Kind:  UnexpectedCharacter,
+				Pos:   Position{4, 4, 2, 3},
+				Token: Token{Value: "x", Start: Position{4, 4, 2, 3}, End: Position{5, 5, 2, 4}},
+			},
+		},
+		"invalid literal": {
+			input: "truex",
+			want: &SyntaxError{
+				Kind:  InvalidLiteral,
+				Pos:   Position{4, 4, 1, 5},
+				Token: Token{Type: True, Value: "truex", Start: Position{0, 0, 1, 1}, End: Position{5, 5, 1, 6}},
+			},
+		},
+		"invalid number": {
+			input: "[-a]",
+			want: &SyntaxError{
+				Kind:  InvalidNumber,
+				Pos:   Position{2, 2, 1, 3},
+				Token: Token{Type: Number, Value: "-a", Start: Position{1, 1, 1, 2}, End: Position{3, 3, 1, 4}},
+			},
+			wantState: true,
+		},
+		"invalid UTF-8": {
+			input: "[\"a\xffb\"]",
+			want: &SyntaxError{
+				Kind:  InvalidUTF8,
+				Pos:   Position{3, 3, 1, 4},
+				Token: Token{Type: String, Value: "\"a\xff", Start: Position{1, 1, 1, 2}, End: Position{4, 4, 1, 5}},
+			},
+		},
+		"invalid UTF-8 outside strings": {
+			input: "[\xff]",
+			want: &SyntaxError{
+				Kind:  InvalidUTF8,
+				Pos:   Position{1, 1, 1, 2},
+				Token: Token{Value: "\xff", Start: Position{1, 1, 1, 2}, End: Position{2, 2, 1, 3}},
+			},
+		},
+		"unterminated string": {
+			input: `"abc`,
+			want: &SyntaxError{
+				Kind:  InvalidString,
+				Pos:   Position{4, 4, 1, 5},
+				Token: Token{Type: String, Value: `"abc`, Start: Position{0, 0, 1, 1}, End: Position{4, 4, 1, 5}},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Tokenize(Runes(tt.input))
+
+			var got *SyntaxError
+			if !errors.As(err, &got) {
+				t.Fatalf("Tokenize(%s) error: %v (want: *SyntaxError)", tt.input, err)
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(SyntaxError{}, "Err"), cmpopts.IgnoreUnexported(SyntaxError{})); diff != "" {
+				t.Fatalf("Tokenize(%s) mismatch (-want +got):\n%s", tt.input, diff)
+			}
+
+			if (errors.Unwrap(err) != nil) != tt.wantState {
+				t.Fatalf("Tokenize(%s) unwrap: %v (want wrapped: %v)", tt.input, errors.Unwrap(err), tt.wantState)
+			}
+		})
+	}
+}
+
+func TestTokenize_lenient(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  []Token
+	}{
+		"control character": {
+			input: "\"a\tb\"",
+			want: []Token{
+				{Type: String, Value: "\"a\tb\""},
+			},
+		},
+		"lone surrogate": {
+			input: `"\uD800"`,
+			want: []Token{
+				{Type: String, Value: `"\uD800"`},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Tokenize([]rune(tt.input), Lenient())
+			if err != nil {
+				t.Fatalf("Tokenize(%s) error: %v", tt.input, err)
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(Token{}, "Start", "End")); diff != "" {
+				t.Fatalf("Tokenize(%s) mismatch (-want +got):\n%s", tt.input, diff)
+			}
+
+			bytes, err := TokenizeBytes([]byte(tt.input), Lenient())
+			if err != nil {
+				t.Fatalf("TokenizeBytes(%s) error: %v", tt.input, err)
+			}
+			if got := string(bytes[0].Value); got != tt.want[0].Value {
+				t.Fatalf("TokenizeBytes(%s) = %s, want %s", tt.input, got, tt.want[0].Value)
+			}
+		})
+	}
+}